@@ -1,12 +1,27 @@
 package conf
 
-import "github.com/ZalgoNoise/goauth-cli/oauth"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ZalgoNoise/goauth-cli/oauth"
+)
 
 // GoAuth struct represents an instance (execution) of GoAuth
 type GoAuth struct {
-	Conf           *GoAuthConf
-	ClientID       *oauth.ClientID
-	ServiceAccount *oauth.ServiceAccount
+	Conf            *GoAuthConf
+	ClientID        *oauth.ClientID
+	ServiceAccount  *oauth.ServiceAccount
+	TokenInfo       *oauth.TokenInfo
+	TokenExchange   *oauth.TokenExchange
+	IDTokenClaims   *oauth.IDTokenClaims
+	Impersonation   *oauth.Impersonation
+	ExternalAccount *oauth.ExternalAccount
+	Metadata        *oauth.MetadataServer
+	Reports         []*oauth.ValidationReport
 }
 
 // NewGoAuth function will create and return a new GoAuth object
@@ -20,10 +35,29 @@ func NewGoAuth() *GoAuth {
 // OnStart method will list the actions to take upon setting up
 // a new GoAuth instance
 func (g *GoAuth) OnStart() {
-	if g.Conf.IsClientID != false {
+	if g.Conf.IsValidate != false {
+		g.ExecValidate()
+		return
+	} else if g.Conf.IsRevoke != false {
+		g.ExecRevoke()
+	} else if g.Conf.IsInspect != false {
+		g.ExecInspect()
+	} else if g.Conf.IsClientID != false {
 		g.ExecClientID()
+	} else if g.Conf.IsClientCredentials != false {
+		g.ExecClientCredentials()
+	} else if g.Conf.IsTokenExchange != false {
+		g.ExecTokenExchange()
 	} else if g.Conf.IsServiceAccount != false {
 		g.ExecServiceAccount()
+	} else if g.Conf.IsADC != false {
+		g.ExecADC()
+	} else if g.Conf.IsMetadata != false {
+		g.ExecMetadata()
+	}
+
+	if g.Conf.Impersonate != "" {
+		g.ExecImpersonation()
 	}
 
 }
@@ -32,12 +66,91 @@ func (g *GoAuth) OnStart() {
 // execution
 func (g *GoAuth) OnFinish() {
 
-	if g.Conf.IsClientID != false && g.ClientID.AccessToken.IsSet() {
+	if g.Conf.IsValidate != false {
+		g.PrintReports()
+		return
+	} else if g.Conf.ConvertFile != "" {
+		if g.Conf.IsNinjaMode == false {
+			fmt.Println(`====
+Service account keyfile written to: ` + g.Conf.ConvertFile + `
+====`)
+		}
+		return
+	} else if g.Impersonation != nil && g.Impersonation.AccessToken.HasIDToken() {
+		g.PrintIDToken(g.Impersonation.AccessToken)
+		return
+	} else if g.Impersonation != nil && g.Impersonation.AccessToken.IsSet() {
+		if g.Conf.IsNinjaMode != false {
+			g.Impersonation.AccessToken.PrintShort()
+			return
+		}
+		g.Impersonation.AccessToken.PrintLong()
+		return
+	} else if g.Conf.IsRevoke != false {
+		if g.Conf.IsNinjaMode == false {
+			fmt.Println(`====
+Token(s) revoked successfully
+====`)
+		}
+		return
+	} else if g.Conf.IsInspect != false {
+		err := g.TokenInfo.Validate(g.Conf.Scopes)
+
+		if g.Conf.IsNinjaMode == false {
+			g.TokenInfo.Print()
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	} else if g.Conf.IsClientID != false && g.ClientID.AccessToken.IsSet() {
 		if g.Conf.IsNinjaMode != false && g.Conf.RefreshToken != "" {
+			if g.Conf.IsOIDC != false {
+				g.ClientID.AccessToken.PrintShortIDToken()
+				return
+			}
 			g.ClientID.AccessToken.PrintShort()
 			return
 		}
 		g.ClientID.AccessToken.PrintLong()
+		if g.IDTokenClaims != nil {
+			g.IDTokenClaims.Print()
+		}
+		return
+	} else if g.Conf.IsClientCredentials != false && g.ClientID.AccessToken.IsSet() {
+		if g.Conf.IsNinjaMode != false {
+			g.ClientID.AccessToken.PrintShort()
+			return
+		}
+		g.ClientID.AccessToken.PrintLong()
+		return
+	} else if g.Conf.IsTokenExchange != false && g.TokenExchange.AccessToken.IsSet() {
+		if g.Conf.IsNinjaMode != false {
+			g.TokenExchange.AccessToken.PrintShort()
+			return
+		}
+		g.TokenExchange.AccessToken.PrintLong()
+		return
+	} else if g.Metadata != nil && g.Metadata.AccessToken.HasIDToken() {
+		g.PrintIDToken(g.Metadata.AccessToken)
+		return
+	} else if g.Metadata != nil && g.Metadata.AccessToken.IsSet() {
+		if g.Conf.IsNinjaMode != false {
+			g.Metadata.AccessToken.PrintShort()
+			return
+		}
+		g.Metadata.AccessToken.PrintLong()
+		return
+	} else if g.ExternalAccount != nil && g.ExternalAccount.AccessToken.IsSet() {
+		if g.Conf.IsNinjaMode != false {
+			g.ExternalAccount.AccessToken.PrintShort()
+			return
+		}
+		g.ExternalAccount.AccessToken.PrintLong()
+		return
+	} else if g.Conf.IsServiceAccount != false && g.Conf.TargetAudience != "" && g.ServiceAccount.AccessToken.HasIDToken() {
+		g.PrintIDToken(g.ServiceAccount.AccessToken)
 		return
 	} else if g.Conf.IsServiceAccount != false && g.ServiceAccount.AccessToken.IsSet() {
 		if g.Conf.IsNinjaMode != false {
@@ -50,35 +163,237 @@ func (g *GoAuth) OnFinish() {
 
 }
 
+// PrintIDToken method will output the input AccessToken's ID Token,
+// either on its own (Ninja Mode) or along with its claims
+func (g *GoAuth) PrintIDToken(token *oauth.AccessToken) {
+	if g.Conf.IsNinjaMode != false {
+		token.PrintShortIDToken()
+		return
+	}
+	token.PrintLongIDToken()
+}
+
 // ExecClientID method will process the actions required for a
 // Client ID account type
 func (g *GoAuth) ExecClientID() {
 	var err error
+	var store oauth.TokenStore
+
+	// the Refresh Token file is read when no Refresh Token is
+	// provided, and updated whenever a new Refresh Token is issued
+	if g.Conf.RefreshFile != "" {
+		store = oauth.NewFileStore(g.Conf.RefreshFile)
+
+		if g.Conf.RefreshToken == "" {
+			if g.Conf.RefreshToken, err = store.Load(); err != nil && !os.IsNotExist(err) {
+				panic(err)
+			}
+		}
+	}
+
+	// without a Client ID value, the secret refers to the
+	// client_secret.json file downloaded from the Cloud Console
+	if g.Conf.AccountName == "" {
+		g.ClientID, err = oauth.NewClientIDFromFile(
+			g.Conf.Secret,
+			g.sourceScopes(),
+			g.Conf.RefreshToken,
+		)
+	} else {
+		g.ClientID, err = oauth.NewClientID(
+			g.Conf.AccountName,
+			g.Conf.Secret,
+			g.sourceScopes(),
+			g.Conf.RefreshToken,
+		)
+	}
 
-	g.ClientID, err = oauth.NewClientID(
-		g.Conf.AccountName,
-		g.Conf.Secret,
-		g.Conf.Scopes,
-		g.Conf.RefreshToken,
-	)
-	
 	if err != nil {
 		panic(err)
 	}
 
+	// authorized_user files already write rotated Refresh Tokens back
+	// to themselves, unless a Refresh Token file is set
+	if store != nil {
+		g.ClientID.Store = store
+	}
+	g.ConfigureClientID()
+
+	// fail before the authorization, rather than on the ID Token
+	if g.Conf.IsOIDC != false && g.ClientID.Endpoint.Issuer == "" {
+		panic(oauth.ErrNoIssuer)
+	}
+
 	if g.ClientID.RefreshToken.HasToken() {
 		g.ClientID.Refresh()
+	} else if g.Conf.IsDevice != false {
+		if err := g.ClientID.Device(); err != nil {
+			panic(err)
+		}
+	} else if g.Conf.IsLoopback != false {
+		if err := g.ClientID.GenLoopback(g.Conf.Port, g.Conf.Timeout); err != nil {
+			panic(err)
+		}
 	} else {
 		g.ClientID.Gen()
 	}
+
+	if g.Conf.IsOIDC != false {
+		if g.IDTokenClaims, err = g.ClientID.VerifyIDToken(); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// ExecClientCredentials method will process the actions required
+// for a Client Credentials account type
+func (g *GoAuth) ExecClientCredentials() {
+	var err error
+
+	if g.Conf.AccountName == "" {
+		g.ClientID, err = oauth.NewClientCredentialsFromFile(
+			g.Conf.Secret,
+			g.Conf.Scopes,
+		)
+	} else {
+		g.ClientID, err = oauth.NewClientCredentials(
+			g.Conf.AccountName,
+			g.Conf.Secret,
+			g.Conf.Scopes,
+		)
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	g.ConfigureClientID()
+
+	if err := g.ClientID.ClientCredentials(g.Conf.Audience); err != nil {
+		panic(err)
+	}
+}
+
+// ExecTokenExchange method will process the actions required to
+// exchange a subject token for a new token, at an STS endpoint
+func (g *GoAuth) ExecTokenExchange() {
+	var err error
+
+	g.TokenExchange, err = oauth.NewTokenExchange(
+		g.Conf.STSURL,
+		g.Conf.SubjectToken,
+		g.Conf.SubjectTokenType,
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	if g.Conf.ActorToken != "" {
+		g.TokenExchange.SetActor(g.Conf.ActorToken, g.Conf.ActorTokenType)
+	}
+	g.TokenExchange.SetRequestedTokenType(g.Conf.RequestedTokenType)
+	g.TokenExchange.SetClient(g.Conf.AccountName, g.Conf.Secret)
+	g.TokenExchange.Audience = g.Conf.Audience
+	g.TokenExchange.Resource = g.Conf.Resource
+	g.TokenExchange.Scopes = g.Conf.Scopes
+
+	if err := g.TokenExchange.Exchange(); err != nil {
+		panic(err)
+	}
+}
+
+// ConfigureClientID method will apply the authorization server and
+// client authentication settings to the GoAuth's ClientID
+func (g *GoAuth) ConfigureClientID() {
+	if g.Conf.Issuer != "" {
+		if err := g.ClientID.Discover(g.Conf.Issuer, "", g.Conf.DiscoveryTTL); err != nil {
+			panic(err)
+		}
+	}
+	g.ClientID.SetEndpoint(g.Conf.Endpoint)
+
+	// Google's issuer and keys don't apply to custom endpoints, which
+	// only carry an issuer when discovered
+	if g.Conf.Issuer == "" && g.Conf.Endpoint != nil && (g.Conf.Endpoint.AuthURL != "" || g.Conf.Endpoint.TokenURL != "") {
+		g.ClientID.Endpoint.Issuer = ""
+		g.ClientID.Endpoint.JWKSURL = ""
+	}
+
+	if g.Conf.AuthMethod == oauth.AuthMethodPrivateKeyJWT {
+		// the secret refers to the private key file
+		if err := g.ClientID.LoadAssertionKey(g.Conf.Secret, g.Conf.KeyID); err != nil {
+			panic(err)
+		}
+	} else if g.Conf.AuthMethod != "" {
+		g.ClientID.SetAuthMethod(g.Conf.AuthMethod)
+	}
+
+	if g.Conf.PKCEMethod != "" {
+		g.ClientID.SetPKCEMethod(g.Conf.PKCEMethod)
+	}
+
+	if g.Conf.AuthParams != nil {
+		g.ClientID.AuthParams = g.Conf.AuthParams
+	}
 }
 
 // ExecServiceAccount method will process the actions required for a
 // Service Account account type
 func (g *GoAuth) ExecServiceAccount() {
+	if oauth.IsPKCS12(g.Conf.Secret) {
+		g.ExecP12()
+		return
+	}
+
+	// Workload Identity Federation configurations and authorized_user
+	// files are provided in place of a service account keyfile
+	credType, err := oauth.CredentialType(g.Conf.Secret)
+	if err != nil {
+		panic(err)
+	}
+	if credType == oauth.CredentialTypeExternalAccount || credType == oauth.CredentialTypeAuthorizedUser {
+		// these only issue Access Tokens; an ID Token is generated for
+		// an impersonated service account instead
+		if g.Conf.IsSelfSigned != false {
+			panic(errors.New(`Self-signed JWTs require a service account keyfile, not an ` + credType + ` file`))
+		}
+		if g.Conf.TargetAudience != "" && g.Conf.Impersonate == "" {
+			panic(errors.New(`ID Tokens can't be issued for an ` + credType + ` file - pass -impersonate to generate one for a service account`))
+		}
+
+		if credType == oauth.CredentialTypeExternalAccount {
+			g.ExecExternalAccount()
+		} else {
+			g.ExecAuthorizedUser()
+		}
+		return
+	}
+
+	if g.Conf.IsSelfSigned != false {
+		g.ServiceAccount = oauth.NewServiceAccountSelfSigned(
+			g.Conf.Secret,
+			g.Conf.Audience,
+			g.Conf.Scopes,
+		)
+		return
+	}
+
+	// when impersonating, the ID Token is issued for the target
+	// service account instead
+	if g.Conf.TargetAudience != "" && g.Conf.Impersonate == "" {
+		g.ServiceAccount = oauth.NewServiceAccountIDToken(
+			g.Conf.Secret,
+			g.Conf.TargetAudience,
+		)
+
+		g.ServiceAccount.Auth()
+		return
+	}
+
 	g.ServiceAccount = oauth.NewServiceAccount(
 		g.Conf.Secret,
-		g.Conf.Scopes,
+		g.sourceScopes(),
 		g.Conf.Subscriber,
 	)
 
@@ -86,18 +401,370 @@ func (g *GoAuth) ExecServiceAccount() {
 
 }
 
+// ExecP12 method will process the actions required for a legacy
+// PKCS#12 (.p12) service account key, which is either converted into
+// a JSON keyfile, or used in the same way as JSON keyfiles. PBES2 /
+// AES-encrypted .p12 files are not supported
+func (g *GoAuth) ExecP12() {
+	var err error
+
+	g.ServiceAccount, err = oauth.NewServiceAccountFromP12(
+		g.Conf.Secret,
+		g.Conf.P12Password,
+		g.Conf.AccountName,
+	)
+	if err != nil {
+		panic(err)
+	}
+	g.ServiceAccount.PrivateKeyID = g.Conf.KeyID
+
+	if g.Conf.ConvertFile != "" {
+		if err := g.ServiceAccount.WriteKeyfile(g.Conf.ConvertFile); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	if g.Conf.IsSelfSigned != false {
+		g.ServiceAccount.InitSelfSigned(g.Conf.Audience, g.Conf.Scopes)
+		return
+	}
+
+	if g.Conf.TargetAudience != "" && g.Conf.Impersonate == "" {
+		g.ServiceAccount.InitIDToken(g.Conf.TargetAudience)
+	} else {
+		g.ServiceAccount.Init(g.sourceScopes(), g.Conf.Subscriber)
+	}
+
+	g.ServiceAccount.Auth()
+}
+
+// ExecAuthorizedUser method will process the actions required for an
+// authorized_user credentials file, refreshing its Refresh Token. The
+// configuration is updated to a Client ID type, so that the output
+// follows the same path
+func (g *GoAuth) ExecAuthorizedUser() {
+	var err error
+
+	if g.ClientID, err = oauth.NewClientIDFromAuthorizedUser(g.Conf.Secret, "", g.Conf.RefreshToken); err != nil {
+		panic(err)
+	}
+
+	g.Conf.IsClientID = true
+	g.Conf.RefreshToken = g.ClientID.RefreshToken.GetToken()
+	g.ClientID.Refresh()
+}
+
+// ExecExternalAccount method will process the actions required for
+// an external account (Workload Identity Federation) credential type
+func (g *GoAuth) ExecExternalAccount() {
+	var err error
+
+	if g.ExternalAccount, err = oauth.ReadExternalAccount(g.Conf.Secret); err != nil {
+		panic(err)
+	}
+
+	if err := g.ExternalAccount.Auth(g.sourceScopes()); err != nil {
+		panic(err)
+	}
+}
+
+// ExecADC method will resolve the Application Default Credentials,
+// and process the actions required for their credential type. The
+// configuration is updated to match the resolved credential type, so
+// that the output follows the same path
+func (g *GoAuth) ExecADC() {
+	file, err := oauth.FindDefaultCredentials()
+	if err != nil {
+		panic(err)
+	}
+
+	if file == "" {
+		g.ExecMetadata()
+		return
+	}
+
+	credType, err := oauth.CredentialType(file)
+	if err != nil {
+		panic(err)
+	}
+
+	switch credType {
+	case oauth.CredentialTypeServiceAccount:
+		if g.Conf.Scopes == "" {
+			g.Conf.Scopes = oauth.CloudPlatformScope
+		}
+		g.Conf.IsServiceAccount = true
+		g.Conf.Secret = file
+		g.ExecServiceAccount()
+
+	case oauth.CredentialTypeExternalAccount:
+		g.Conf.Secret = file
+		g.ExecExternalAccount()
+
+	case oauth.CredentialTypeAuthorizedUser:
+		g.Conf.Secret = file
+		g.ExecAuthorizedUser()
+
+	default:
+		panic(errors.New(`Unsupported credential type in ` + file + `: ` + credType))
+	}
+}
+
+// ExecMetadata method will request an Access Token (or an ID Token,
+// if a target audience is set) for the attached service account from
+// the metadata server
+func (g *GoAuth) ExecMetadata() {
+	var err error
+
+	g.Metadata = oauth.NewMetadataServer()
+	g.Metadata.SetAccount(g.Conf.AccountName)
+	g.Metadata.SetScopes(g.sourceScopes())
+
+	// when impersonating, the ID Token is issued for the target
+	// service account instead
+	if g.Conf.TargetAudience != "" && g.Conf.Impersonate == "" {
+		err = g.Metadata.IDToken(g.Conf.TargetAudience)
+	} else {
+		err = g.Metadata.Token()
+	}
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+// ExecImpersonation method will generate an Access Token for the
+// target service account, using the Client ID's or Service Account's
+// Access Token as the source credentials
+func (g *GoAuth) ExecImpersonation() {
+	var err error
+	var source string
+
+	if g.ClientID != nil {
+		source = g.ClientID.AccessToken.Token
+	} else if g.ServiceAccount != nil {
+		source = g.ServiceAccount.AccessToken.Token
+	} else if g.ExternalAccount != nil {
+		source = g.ExternalAccount.AccessToken.Token
+	} else if g.Metadata != nil {
+		source = g.Metadata.AccessToken.Token
+	}
+
+	g.Impersonation, err = oauth.NewImpersonation(g.Conf.Impersonate, g.Conf.Scopes)
+	if err != nil {
+		panic(err)
+	}
+
+	g.Impersonation.SetDelegates(g.Conf.Delegates)
+	g.Impersonation.SetLifetime(g.Conf.Lifetime)
+
+	if g.Conf.TargetAudience != "" {
+		err = g.Impersonation.GenerateIDToken(source, g.Conf.TargetAudience)
+	} else {
+		err = g.Impersonation.GenerateAccessToken(source)
+	}
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+// sourceScopes method returns the scopes to request for the
+// configured credentials. When impersonating a service account, the
+// configured scopes apply to the target service account, and the
+// source credentials only need to call the IAM Credentials API
+func (g *GoAuth) sourceScopes() string {
+	if g.Conf.Impersonate != "" {
+		return oauth.CloudPlatformScope
+	}
+	return g.Conf.Scopes
+}
+
+// ExecValidate method will validate the credentials files provided
+// in the configuration
+func (g *GoAuth) ExecValidate() {
+	for _, file := range g.Conf.Files {
+		g.Reports = append(g.Reports, oauth.Validate(file))
+	}
+}
+
+// PrintReports method will output the validation reports, either as
+// text or as a JSON array, exiting with an error code if any of the
+// files is invalid. In Ninja Mode, only the exit code is returned
+func (g *GoAuth) PrintReports() {
+	valid := true
+	for _, r := range g.Reports {
+		valid = valid && r.Valid
+	}
+
+	if g.Conf.IsJSON != false {
+		out, err := json.MarshalIndent(g.Reports, "", "  ")
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(out))
+	} else if g.Conf.IsNinjaMode == false {
+		for _, r := range g.Reports {
+			r.Print()
+		}
+	}
+
+	if !valid {
+		os.Exit(1)
+	}
+}
+
+// ExecRevoke method will revoke the Access Token and / or Refresh
+// Token provided in the configuration. If a Client ID is provided,
+// the tokens are revoked on its behalf
+func (g *GoAuth) ExecRevoke() {
+	if g.Conf.AccountName != "" || g.Conf.Secret != "" {
+		g.ExecRevokeClientID()
+		return
+	}
+
+	token := &oauth.AccessToken{
+		Token:        g.Conf.AccessToken,
+		RefreshToken: g.Conf.RefreshToken,
+	}
+
+	endpoint := oauth.GoogleEndpoint()
+
+	if g.Conf.Issuer != "" {
+		metadata, err := oauth.DiscoverCached(g.Conf.Issuer, "", g.Conf.DiscoveryTTL)
+		if err != nil {
+			panic(err)
+		}
+		endpoint = metadata.Endpoint()
+	}
+	endpoint.Merge(g.Conf.Endpoint)
+
+	if err := token.Revoke(endpoint.RevokeURL, true); err != nil {
+		panic(err)
+	}
+}
+
+// ExecRevokeClientID method will revoke the Access Token and / or
+// Refresh Token provided in the configuration, authenticating as the
+// Client ID, as required by RFC 7009 for confidential clients (like
+// Keycloak or Okta clients). Public clients [-auth-method none] only
+// send their Client ID
+func (g *GoAuth) ExecRevokeClientID() {
+	var err error
+
+	// scopes aren't needed to revoke tokens, and are only optional
+	// with the Client Credentials and public client constructors
+	switch {
+	case g.Conf.AccountName == "":
+		// the secret refers to a client_secret.json or authorized_user
+		// file
+		if credType, _ := oauth.CredentialType(g.Conf.Secret); credType == oauth.CredentialTypeAuthorizedUser {
+			g.ClientID, err = oauth.NewClientIDFromAuthorizedUser(g.Conf.Secret, "", g.Conf.RefreshToken)
+		} else {
+			g.ClientID, err = oauth.NewClientCredentialsFromFile(g.Conf.Secret, "")
+		}
+	case g.Conf.AuthMethod == oauth.AuthMethodNone || g.Conf.AuthMethod == oauth.AuthMethodPrivateKeyJWT:
+		g.ClientID, err = oauth.NewPublicClientID(g.Conf.AccountName, "")
+	default:
+		g.ClientID, err = oauth.NewClientCredentials(g.Conf.AccountName, g.Conf.Secret, "")
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	g.ClientID.RefreshToken.SetToken(g.Conf.RefreshToken)
+	g.ClientID.AccessToken.Token = g.Conf.AccessToken
+	g.ConfigureClientID()
+
+	if err := g.ClientID.Revoke(true); err != nil {
+		panic(err)
+	}
+}
+
+// ExecInspect method will query the tokeninfo (or introspection)
+// endpoint for the Access Token or ID Token provided in the
+// configuration
+func (g *GoAuth) ExecInspect() {
+	var err error
+
+	if g.Conf.IntrospectURL != "" {
+		token := g.Conf.AccessToken
+		if token == "" {
+			token = g.Conf.IDToken
+		}
+
+		g.TokenInfo, err = oauth.Introspect(
+			g.Conf.IntrospectURL,
+			token,
+			g.Conf.AccountName,
+			g.Conf.Secret,
+		)
+	} else if g.Conf.IDToken != "" {
+		g.TokenInfo, err = oauth.Inspect(oauth.GoogleTokenInfoURL, g.Conf.IDToken, oauth.TokenTypeIDToken)
+	} else {
+		g.TokenInfo, err = oauth.Inspect(oauth.GoogleTokenInfoURL, g.Conf.AccessToken, oauth.TokenTypeAccessToken)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
 // GoAuthConf struct will represent the configuration for this
 // instance of GoAuth
 type GoAuthConf struct {
-	IsClientID       bool
-	IsServiceAccount bool
-	IsWebUI          bool
-	IsNinjaMode      bool
-	AccountName      string
-	Secret           string
-	Scopes           string
-	Subscriber       string
-	RefreshToken     string
+	IsClientID          bool
+	IsServiceAccount    bool
+	IsClientCredentials bool
+	IsTokenExchange     bool
+	IsADC               bool
+	IsMetadata          bool
+	IsOIDC              bool
+	IsSelfSigned        bool
+	IsWebUI             bool
+	IsNinjaMode         bool
+	IsLoopback          bool
+	IsDevice            bool
+	IsRevoke            bool
+	IsInspect           bool
+	IsValidate          bool
+	IsJSON              bool
+	AccountName         string
+	Secret              string
+	Scopes              string
+	Subscriber          string
+	Audience            string
+	SubjectToken        string
+	SubjectTokenType    string
+	ActorToken          string
+	ActorTokenType      string
+	RequestedTokenType  string
+	Resource            string
+	STSURL              string
+	RefreshToken        string
+	RefreshFile         string
+	AccessToken         string
+	IDToken             string
+	IntrospectURL       string
+	PKCEMethod          string
+	AuthMethod          string
+	KeyID               string
+	P12Password         string
+	ConvertFile         string
+	Impersonate         string
+	TargetAudience      string
+	Delegates           string
+	Lifetime            time.Duration
+	Files               []string
+	AuthParams          *oauth.AuthParams
+	Endpoint            *oauth.Endpoint
+	Issuer              string
+	DiscoveryTTL        time.Duration
+	Port                int
+	Timeout             time.Duration
 }
 
 // NewClientID method will create a new Client ID object based
@@ -126,3 +793,88 @@ func (c *GoAuthConf) NewServiceAccount(keyfile, scopes, subscriber string, ninja
 		Subscriber:       subscriber,
 	}
 }
+
+// NewRevoke method will create a new token revocation configuration
+// based on its available input parameters
+func (c *GoAuthConf) NewRevoke(refreshToken, accessToken string, ninjaMode bool) *GoAuthConf {
+	return &GoAuthConf{
+		IsRevoke:     true,
+		IsNinjaMode:  ninjaMode,
+		RefreshToken: refreshToken,
+		AccessToken:  accessToken,
+	}
+}
+
+// NewInspect method will create a new token inspection configuration
+// based on its available input parameters. The Client ID and secret
+// are only used for RFC 7662 introspection endpoints
+func (c *GoAuthConf) NewInspect(accessToken, idToken, scopes, introspectURL, clientid, secret string, ninjaMode bool) *GoAuthConf {
+	return &GoAuthConf{
+		IsInspect:     true,
+		IsNinjaMode:   ninjaMode,
+		AccessToken:   accessToken,
+		IDToken:       idToken,
+		Scopes:        scopes,
+		IntrospectURL: introspectURL,
+		AccountName:   clientid,
+		Secret:        secret,
+	}
+}
+
+// NewClientCredentials method will create a new Client Credentials
+// object based on its available input parameters
+func (c *GoAuthConf) NewClientCredentials(clientid, secret, scopes, audience string, ninjaMode bool) *GoAuthConf {
+	return &GoAuthConf{
+		IsClientCredentials: true,
+		IsNinjaMode:         ninjaMode,
+		AccountName:         clientid,
+		Secret:              secret,
+		Scopes:              scopes,
+		Audience:            audience,
+	}
+}
+
+// NewTokenExchange method will create a new Token Exchange
+// configuration based on its available input parameters
+func (c *GoAuthConf) NewTokenExchange(subjectToken, subjectTokenType, scopes, audience string, ninjaMode bool) *GoAuthConf {
+	return &GoAuthConf{
+		IsTokenExchange:  true,
+		IsNinjaMode:      ninjaMode,
+		SubjectToken:     subjectToken,
+		SubjectTokenType: subjectTokenType,
+		Scopes:           scopes,
+		Audience:         audience,
+	}
+}
+
+// NewADC method will create a new Application Default Credentials
+// configuration based on its available input parameters
+func (c *GoAuthConf) NewADC(scopes string, ninjaMode bool) *GoAuthConf {
+	return &GoAuthConf{
+		IsADC:       true,
+		IsNinjaMode: ninjaMode,
+		Scopes:      scopes,
+	}
+}
+
+// NewMetadata method will create a new metadata server configuration
+// based on its available input parameters
+func (c *GoAuthConf) NewMetadata(account, scopes string, ninjaMode bool) *GoAuthConf {
+	return &GoAuthConf{
+		IsMetadata:  true,
+		IsNinjaMode: ninjaMode,
+		AccountName: account,
+		Scopes:      scopes,
+	}
+}
+
+// NewValidate method will create a new credentials file validation
+// configuration based on its available input parameters
+func (c *GoAuthConf) NewValidate(files []string, json, ninjaMode bool) *GoAuthConf {
+	return &GoAuthConf{
+		IsValidate:  true,
+		IsJSON:      json,
+		IsNinjaMode: ninjaMode,
+		Files:       files,
+	}
+}
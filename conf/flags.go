@@ -3,10 +3,16 @@ package conf
 import (
 	"errors"
 	"flag"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ZalgoNoise/goauth-cli/oauth"
 )
 
 const (
-	noOptError = `At least one option must be set: Client ID or Service Account`
+	noOptError = `At least one option must be set: Client ID, Service Account, Client Credentials, Token Exchange, Application Default Credentials, metadata server, token revocation, inspection or validation`
 	noRefError = `No value provided for option: `
 )
 
@@ -18,43 +24,261 @@ func GetOpts() *GoAuthConf {
 	// execution modes
 	setClientID := flag.Bool("c", false, "Client ID as a credential type")
 	setServiceAccount := flag.Bool("s", false, "Service Account as a credential type")
+	setTokenExchange := flag.Bool("e", false, "Token Exchange (RFC 8693): exchanges a subject token [-subject-token {token}] for a new token")
+	setClientCredentials := flag.Bool("cc", false, "Client Credentials (machine-to-machine) as a credential type")
+	setADC := flag.Bool("adc", false, "Application Default Credentials: finds the credentials from the GOOGLE_APPLICATION_CREDENTIALS environment variable, gcloud's application_default_credentials.json file, or the metadata server")
+	setMetadata := flag.Bool("m", false, "Metadata server as a credential source, for the attached service account [-account {email}] on Google Cloud. The host can be overridden with the GCE_METADATA_HOST environment variable")
+	setInspect := flag.Bool("inspect", false, "Inspects the provided Access Token [-a {token}] or ID Token [-id-token {token}], checking it against the required scopes [-x {scopes}]")
+	setValidate := flag.Bool("validate", false, "Validates the provided credentials file(s) [-k {file}, or as arguments]: service account keyfiles, external_account, authorized_user and client_secret.json files")
+	setRevoke := flag.Bool("revoke", false, "Revokes the provided Access Token [-a {token}] and / or Refresh Token [-r {token}]. With a Client ID [-i {id} -k {secret}, or -k {file}], the client is authenticated on the request")
 
 	// auth settings (short form)
-	accountName := flag.String("i", "", "Client ID name / value. Service accounts only refer to the keyfile [-k {file}]")
-	secret := flag.String("k", "", "Secret or key for the credentials. A string for a Client ID Secret, a path to a JSON file for Service Accounts")
+	accountName := flag.String("i", "", "Client ID name / value. Service accounts only refer to the keyfile [-k {file}], except for .p12 keys where it is the service account email. If omitted for Client IDs, [-k {file}] refers to a client_secret.json (or authorized_user) file")
+	secret := flag.String("k", "", "Secret or key for the credentials. A string for a Client ID Secret (or a path to a client_secret.json or authorized_user file), a path to a JSON file for Service Accounts (or to an external_account file, or to a .p12 key)")
 	scopes := flag.String("x", "", "Space-delimited list of scopes to use in the request")
 	subscriber := flag.String("u", "", "[optional] Impersonated user (Service Accounts)")
+	audience := flag.String("audience", "", "[optional] Audience of the requested token (Client Credentials, Token Exchange and self-signed JWTs)")
+	account := flag.String("account", "default", "[optional] Email of the service account to request tokens for (metadata server)")
 	refresh := flag.String("r", "", "[optional] Refresh Token (Client IDs)")
+	refreshFile := flag.String("refresh-file", "", "[optional] File holding the Refresh Token (Client IDs). It is read when no Refresh Token is provided, and updated whenever a new (or rotated) Refresh Token is issued")
+	access := flag.String("a", "", "[optional] Access Token (token revocation and inspection)")
 
 	// auth settings (long form)
-	accountNameLong := flag.String("id", "", "Client ID name / value. Service accounts only refer to the keyfile [-k {file}]")
-	secretLong := flag.String("key", "", "Secret or key for the credentials. A string for a Client ID Secret, a path to a JSON file for Service Accounts")
+	accountNameLong := flag.String("id", "", "Client ID name / value. Service accounts only refer to the keyfile [-k {file}], except for .p12 keys where it is the service account email. If omitted for Client IDs, [-k {file}] refers to a client_secret.json (or authorized_user) file")
+	secretLong := flag.String("key", "", "Secret or key for the credentials. A string for a Client ID Secret (or a path to a client_secret.json or authorized_user file), a path to a JSON file for Service Accounts (or to an external_account file, or to a .p12 key)")
 	scopesLong := flag.String("scope", "", "Space-delimited list of scopes to use in the request")
 	subscriberLong := flag.String("user", "", "[optional] Impersonated user (Service Accounts)")
 	refreshLong := flag.String("refresh", "", "[optional] Refresh Token (Client IDs)")
+	accessLong := flag.String("access", "", "[optional] Access Token (token revocation and inspection)")
+	idToken := flag.String("id-token", "", "[optional] ID Token (token inspection)")
+	introspectURL := flag.String("introspect-url", "", "[optional] RFC 7662 introspection endpoint, instead of Google's tokeninfo (token inspection)")
+
+	// authorization request parameters (Client IDs)
+	loginHint := flag.String("login-hint", "", "[optional] Email address of the account to preselect in the consent screen (Client IDs)")
+	hostedDomain := flag.String("hd", "", "[optional] Workspace domain to restrict the consent screen to (Client IDs)")
+	prompt := flag.String("prompt", oauth.PromptConsent, "[optional] Space-delimited list of prompts for the consent screen: none, consent and / or select_account (Client IDs)")
+	accessType := flag.String("access-type", oauth.AccessTypeOffline, "[optional] Access type (Client IDs): offline (issues a Refresh Token) or online")
+	includeGrantedScopes := flag.Bool("include-granted-scopes", false, "[optional] Includes previously granted scopes in the new grant, for incremental authorization (Client IDs)")
+
+	// authorization server endpoints (Client IDs), defaulting to Google's
+	authURL := flag.String("auth-url", "", "[optional] Authorization endpoint URL (Client IDs)")
+	tokenURL := flag.String("token-url", "", "[optional] Token endpoint URL (Client IDs)")
+	revokeURL := flag.String("revoke-url", "", "[optional] Token revocation endpoint URL (Client IDs and token revocation)")
+	deviceURL := flag.String("device-url", "", "[optional] Device authorization endpoint URL (Client IDs)")
+	issuer := flag.String("issuer", "", "[optional] Issuer URL, to discover the authorization server endpoints through OpenID Connect / RFC 8414 metadata (Client IDs)")
+	discoveryTTL := flag.Duration("discovery-ttl", oauth.DiscoveryTTL, "[optional] Time to cache the discovered issuer metadata on disk")
+
+	// token exchange settings
+	subjectToken := flag.String("subject-token", "", "Subject token to exchange (Token Exchange). Either the token itself, @{file} to read it from a file, or - to read it from stdin")
+	subjectTokenType := flag.String("subject-token-type", "access_token", "[optional] Subject token type (Token Exchange): access_token, refresh_token, id_token, jwt, saml1, saml2 or a token type URI")
+	actorToken := flag.String("actor-token", "", "[optional] Actor token, for delegation (Token Exchange). Either the token itself, or @{file} to read it from a file")
+	actorTokenType := flag.String("actor-token-type", "access_token", "[optional] Actor token type (Token Exchange)")
+	requestedTokenType := flag.String("requested-token-type", "access_token", "[optional] Requested token type (Token Exchange)")
+	resource := flag.String("resource", "", "[optional] Resource URI of the target service (Token Exchange)")
+	stsURL := flag.String("sts-url", oauth.GoogleSTSURL, "[optional] Security Token Service endpoint URL (Token Exchange)")
+
+	// legacy PKCS#12 (.p12) service account keys
+	p12Password := flag.String("p12-password", oauth.P12Password, "[optional] Password of the .p12 key [-k {file}], along with the service account email [-i {email}] (Service Accounts). Only legacy .p12 keys (as issued by Google) are supported - PBES2 / AES-encrypted .p12 files are not")
+	convert := flag.String("convert", "", "[optional] Converts the .p12 key [-k {file}] into a JSON keyfile, written to the input path, instead of requesting a token (Service Accounts)")
+
+	// service account impersonation settings (Client IDs and Service Accounts)
+	impersonate := flag.String("impersonate", "", "[optional] Email of the target service account to impersonate, through the IAM Credentials API. The requested scopes [-x {scopes}] apply to the target service account (Client IDs and Service Accounts)")
+	delegates := flag.String("delegates", "", "[optional] Comma-separated list of service accounts in the impersonation delegation chain")
+	targetAudience := flag.String("target-audience", "", "[optional] Target audience (like a Cloud Run service URL) to request a Google-signed ID Token for, instead of an Access Token (Service Accounts, metadata server and impersonated service accounts)")
+	lifetime := flag.Duration("lifetime", time.Hour, "[optional] Lifetime of the impersonated service account's Access Token")
 
 	// runtime options
+	jsonOutput := flag.Bool("json", false, "JSON output: returns the validation reports as a JSON array (credentials file validation)")
 	ninjaMode := flag.Bool("z", false, "Ninja Mode: returns only the access tokens as a string, so the output can be fed into other programs or apps")
+	loopback := flag.Bool("l", false, "Loopback Mode: captures the Access Code (Client IDs) through a temporary HTTP listener on 127.0.0.1, instead of pasting it in the terminal")
+	selfSigned := flag.Bool("self-signed", false, "Self-signed JWT Mode: returns a JWT signed by the service account's key as the Access Token, for the audience [-audience {url}] and / or scopes [-x {scopes}], without a request to the token endpoint (Service Accounts)")
+	oidc := flag.Bool("oidc", false, "OpenID Connect Mode: requests the openid scope, and validates and outputs the ID Token and its claims (Client IDs). In Ninja Mode, only the ID Token is returned")
+	device := flag.Bool("d", false, "Device Mode: authorizes the Client ID with a user code entered on another device, for headless environments")
+	port := flag.Int("port", 0, "[optional] Port for the loopback listener (Client IDs). Defaults to a random available port")
+	pkceMethod := flag.String("pkce", oauth.PKCEMethodS256, "[optional] PKCE code challenge method (Client IDs): S256 or plain")
+	authMethod := flag.String("auth-method", oauth.AuthMethodClientSecretPost, "[optional] Client authentication method on token requests (Client IDs): client_secret_post, client_secret_basic, private_key_jwt or none. With private_key_jwt, [-k {file}] refers to a PEM private key file")
+	keyID := flag.String("kid", "", "[optional] Key ID of the private key, for the private_key_jwt client authentication (Client IDs) or for .p12 keys (Service Accounts)")
+	timeout := flag.Duration("timeout", 5*time.Minute, "[optional] Time to wait for the authorization redirect in Loopback Mode (Client IDs)")
 
 	flag.Parse()
 
-	if *setClientID != false {
+	endpoint := &oauth.Endpoint{
+		AuthURL:   *authURL,
+		TokenURL:  *tokenURL,
+		RevokeURL: *revokeURL,
+		DeviceURL: *deviceURL,
+	}
+
+	if *setValidate != false {
+		files := flag.Args()
+		if file := StringCheck(*secret, *secretLong, ""); file != "" {
+			files = append([]string{file}, files...)
+		}
+		if len(files) == 0 {
+			panic(errors.New(noRefError + "Credentials file(s) to validate"))
+		}
+
+		return cfg.NewValidate(files, *jsonOutput, *ninjaMode)
+
+	} else if *setRevoke != false {
+		cfg = cfg.NewRevoke(
+			StringCheck(*refresh, *refreshLong, ""),
+			StringCheck(*access, *accessLong, ""),
+			*ninjaMode,
+		)
+		cfg.AccountName = StringCheck(*accountName, *accountNameLong, "")
+		cfg.Secret = StringCheck(*secret, *secretLong, "")
+		cfg.AuthMethod = *authMethod
+		cfg.KeyID = *keyID
+		cfg.Endpoint = endpoint
+		cfg.Issuer = *issuer
+		cfg.DiscoveryTTL = *discoveryTTL
+
+		return cfg
+
+	} else if *setInspect != false {
+		return cfg.NewInspect(
+			StringCheck(*access, *accessLong, ""),
+			*idToken,
+			StringCheck(*scopes, *scopesLong, ""),
+			*introspectURL,
+			StringCheck(*accountName, *accountNameLong, ""),
+			StringCheck(*secret, *secretLong, ""),
+			*ninjaMode,
+		)
+
+	} else if *setClientID != false {
 
-		return cfg.NewClientID(
-			StringCheck(*accountName, *accountNameLong, "Client ID name"),
-			StringCheck(*secret, *secretLong, "Client ID secret"),
-			StringCheck(*scopes, *scopesLong, "Authorization scopes"),
+		cfg = cfg.NewClientID(
+			StringCheck(*accountName, *accountNameLong, ""),
+			StringCheck(*secret, *secretLong, "Client ID secret (or client_secret.json file)"),
+			StringCheck(*scopes, *scopesLong, ""),
 			StringCheck(*refresh, *refreshLong, ""),
 			*ninjaMode,
 		)
+		cfg.RefreshFile = *refreshFile
+		cfg.Impersonate = *impersonate
+		cfg.Delegates = *delegates
+		cfg.Lifetime = *lifetime
+		if cfg.Impersonate != "" {
+			cfg.TargetAudience = *targetAudience
+		}
+		cfg.IsOIDC = *oidc
+		if cfg.IsOIDC && cfg.Scopes != "" && !strings.Contains(" "+cfg.Scopes+" ", " openid ") {
+			cfg.Scopes = "openid " + cfg.Scopes
+		}
+		cfg.AuthParams = &oauth.AuthParams{
+			AccessType:           *accessType,
+			Prompt:               *prompt,
+			LoginHint:            *loginHint,
+			HostedDomain:         *hostedDomain,
+			IncludeGrantedScopes: *includeGrantedScopes,
+		}
+		cfg.IsLoopback = *loopback
+		cfg.IsDevice = *device
+		cfg.Port = *port
+		cfg.Timeout = *timeout
+		cfg.PKCEMethod = *pkceMethod
+		cfg.AuthMethod = *authMethod
+		cfg.KeyID = *keyID
+		cfg.Endpoint = endpoint
+		cfg.Issuer = *issuer
+		cfg.DiscoveryTTL = *discoveryTTL
+
+		return cfg
+
+	} else if *setTokenExchange != false {
+		cfg = cfg.NewTokenExchange(
+			ReadToken(*subjectToken, "Subject token"),
+			*subjectTokenType,
+			StringCheck(*scopes, *scopesLong, ""),
+			*audience,
+			*ninjaMode,
+		)
+		cfg.ActorToken = ReadToken(*actorToken, "")
+		cfg.ActorTokenType = *actorTokenType
+		cfg.RequestedTokenType = *requestedTokenType
+		cfg.Resource = *resource
+		cfg.STSURL = *stsURL
+		cfg.AccountName = StringCheck(*accountName, *accountNameLong, "")
+		cfg.Secret = StringCheck(*secret, *secretLong, "")
+
+		return cfg
+
+	} else if *setClientCredentials != false {
+		cfg = cfg.NewClientCredentials(
+			StringCheck(*accountName, *accountNameLong, ""),
+			StringCheck(*secret, *secretLong, "Client ID secret (or client_secret.json file)"),
+			StringCheck(*scopes, *scopesLong, ""),
+			*audience,
+			*ninjaMode,
+		)
+		cfg.AuthMethod = *authMethod
+		cfg.KeyID = *keyID
+		cfg.Endpoint = endpoint
+		cfg.Issuer = *issuer
+		cfg.DiscoveryTTL = *discoveryTTL
+
+		return cfg
 
 	} else if *setServiceAccount != false {
-		return cfg.NewServiceAccount(
+		// the scopes are optional when impersonating, as the target
+		// service account defaults to the cloud-platform scope (as do
+		// external accounts), when requesting an ID Token, for
+		// self-signed JWTs with an audience, for authorized_user files,
+		// which are refreshed with their granted scopes, and when
+		// converting .p12 keys
+		scopesRef := "Authorization scopes"
+		credType, _ := oauth.CredentialType(StringCheck(*secret, *secretLong, ""))
+
+		if *impersonate != "" || *targetAudience != "" || (*selfSigned && *audience != "") || *convert != "" ||
+			credType == oauth.CredentialTypeExternalAccount || credType == oauth.CredentialTypeAuthorizedUser {
+			scopesRef = ""
+		}
+
+		cfg = cfg.NewServiceAccount(
 			StringCheck(*secret, *secretLong, "JSON Keyfile for the Service Account, from GCP"),
-			StringCheck(*scopes, *scopesLong, "Authorization scopes"),
+			StringCheck(*scopes, *scopesLong, scopesRef),
 			StringCheck(*subscriber, *subscriberLong, ""),
 			*ninjaMode,
 		)
+		cfg.Impersonate = *impersonate
+		cfg.Delegates = *delegates
+		cfg.Lifetime = *lifetime
+		cfg.TargetAudience = *targetAudience
+		cfg.IsSelfSigned = *selfSigned
+		cfg.Audience = *audience
+		cfg.AccountName = StringCheck(*accountName, *accountNameLong, "")
+		cfg.KeyID = *keyID
+		cfg.P12Password = *p12Password
+		cfg.ConvertFile = *convert
+
+		return cfg
+
+	} else if *setADC != false {
+		cfg = cfg.NewADC(
+			StringCheck(*scopes, *scopesLong, ""),
+			*ninjaMode,
+		)
+		cfg.Impersonate = *impersonate
+		cfg.Delegates = *delegates
+		cfg.Lifetime = *lifetime
+		cfg.TargetAudience = *targetAudience
+
+		return cfg
+
+	} else if *setMetadata != false {
+		cfg = cfg.NewMetadata(
+			*account,
+			StringCheck(*scopes, *scopesLong, ""),
+			*ninjaMode,
+		)
+		cfg.Impersonate = *impersonate
+		cfg.Delegates = *delegates
+		cfg.Lifetime = *lifetime
+		cfg.TargetAudience = *targetAudience
+
+		return cfg
 
 	} else {
 		panic(errors.New(noOptError))
@@ -79,3 +303,31 @@ func StringCheck(short, long, ref string) string {
 	return ""
 
 }
+
+// ReadToken function will return the token referred by the input,
+// which is either the token itself, a path to a file containing it
+// (prefixed with @), or - to read it from stdin. If the input is empty
+// and a reference is set, the process panics with an error
+func ReadToken(input, ref string) string {
+	var buf []byte
+	var err error
+
+	switch {
+	case input == "-":
+		buf, err = ioutil.ReadAll(os.Stdin)
+	case strings.HasPrefix(input, "@"):
+		buf, err = ioutil.ReadFile(strings.TrimPrefix(input, "@"))
+	default:
+		buf = []byte(input)
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	token := strings.TrimSpace(string(buf))
+	if token == "" && ref != "" {
+		panic(errors.New(noRefError + ref))
+	}
+	return token
+}
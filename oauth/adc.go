@@ -0,0 +1,66 @@
+package oauth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const (
+	// CredentialsEnv is the environment variable pointing to the
+	// Application Default Credentials file
+	CredentialsEnv string = `GOOGLE_APPLICATION_CREDENTIALS`
+
+	cloudSDKConfigEnv string = `CLOUDSDK_CONFIG`
+	wellKnownFileName string = `application_default_credentials.json`
+)
+
+// ErrNoDefaultCredentials is returned when no Application Default
+// Credentials are found
+var ErrNoDefaultCredentials = errors.New(`Unable to find Application Default Credentials: set ` + CredentialsEnv + `, run 'gcloud auth application-default login', or run on Google Cloud`)
+
+// FindDefaultCredentials function will resolve the Application
+// Default Credentials, in order: the file in the
+// GOOGLE_APPLICATION_CREDENTIALS environment variable, gcloud's
+// well-known application_default_credentials.json file, and the
+// metadata server. It returns the credentials file, or an empty
+// string if the metadata server is to be used
+func FindDefaultCredentials() (string, error) {
+	if file := os.Getenv(CredentialsEnv); file != "" {
+		if _, err := os.Stat(file); err != nil {
+			return "", errors.New(`Unable to read the credentials file in ` + CredentialsEnv + `: ` + err.Error())
+		}
+		return file, nil
+	}
+
+	if file := WellKnownFile(); file != "" {
+		if _, err := os.Stat(file); err == nil {
+			return file, nil
+		}
+	}
+
+	if NewMetadataServer().OnGCE() {
+		return "", nil
+	}
+
+	return "", ErrNoDefaultCredentials
+}
+
+// WellKnownFile function returns the path to gcloud's Application
+// Default Credentials file, in the gcloud configuration directory
+func WellKnownFile() string {
+	if dir := os.Getenv(cloudSDKConfigEnv); dir != "" {
+		return filepath.Join(dir, wellKnownFileName)
+	}
+
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("APPDATA"), "gcloud", wellKnownFileName)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gcloud", wellKnownFileName)
+}
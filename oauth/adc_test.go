@@ -0,0 +1,88 @@
+package oauth
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setenv function will set (or unset, if empty) the input environment
+// variable for the duration of the test
+func setenv(t *testing.T, key, value string) {
+	prev, ok := os.LookupEnv(key)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, prev)
+			return
+		}
+		os.Unsetenv(key)
+	})
+
+	if value == "" {
+		os.Unsetenv(key)
+		return
+	}
+	os.Setenv(key, value)
+}
+
+func TestFindDefaultCredentials(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), "credentials.json")
+	if err := ioutil.WriteFile(envFile, []byte(`{"type":"service_account"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	gcloudDir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(gcloudDir, wellKnownFileName), []byte(`{"type":"authorized_user"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		env          string
+		gcloudDir    string
+		metadataHost string
+		want         string
+		ok           bool
+	}{
+		{
+			name:      "environment variable",
+			env:       envFile,
+			gcloudDir: gcloudDir,
+			want:      envFile,
+			ok:        true,
+		}, {
+			name:      "missing environment variable file",
+			env:       filepath.Join(gcloudDir, "missing.json"),
+			gcloudDir: gcloudDir,
+			ok:        false,
+		}, {
+			name:      "well-known file",
+			gcloudDir: gcloudDir,
+			want:      filepath.Join(gcloudDir, wellKnownFileName),
+			ok:        true,
+		}, {
+			name:         "metadata server",
+			gcloudDir:    t.TempDir(),
+			metadataHost: "127.0.0.1:8080",
+			want:         "",
+			ok:           true,
+		},
+	}
+
+	for _, test := range tests {
+		setenv(t, CredentialsEnv, test.env)
+		setenv(t, cloudSDKConfigEnv, test.gcloudDir)
+		setenv(t, MetadataHostEnv, test.metadataHost)
+
+		got, err := FindDefaultCredentials()
+		if (err == nil) != test.ok {
+			t.Errorf(`TestFindDefaultCredentials(%s) = %v, expected error to be %v`, test.name, err, !test.ok)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf(`TestFindDefaultCredentials(%s) = %q, expected result to be %q`, test.name, got, test.want)
+		}
+	}
+}
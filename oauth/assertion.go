@@ -0,0 +1,86 @@
+package oauth
+
+import (
+	"errors"
+	"io/ioutil"
+	"time"
+)
+
+const (
+	// AuthMethodPrivateKeyJWT authenticates the client with a JWT
+	// signed by its private key (RFC 7523), instead of a secret
+	AuthMethodPrivateKeyJWT string = `private_key_jwt`
+
+	clientAssertionType     string        = `urn:ietf:params:oauth:client-assertion-type:jwt-bearer`
+	clientAssertionLifetime time.Duration = 5 * time.Minute
+	clientAssertionIDLength int           = 32
+)
+
+// LoadAssertionKey method will read the input PEM private key file
+// and configure the ClientID to authenticate with private_key_jwt,
+// dropping its secret. The key ID is optional, and is set in the
+// client assertions' header when present
+func (c *ClientID) LoadAssertionKey(file, keyID string) error {
+	key, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	return c.SetAssertionKey(string(key), keyID)
+}
+
+// SetAssertionKey method will configure the ClientID to authenticate
+// with private_key_jwt, with the input PEM private key and key ID
+func (c *ClientID) SetAssertionKey(key, keyID string) error {
+	if _, err := newKey([]byte(key)); err != nil {
+		return err
+	}
+
+	c.AssertionKey = key
+	c.AssertionKeyID = keyID
+	c.SetSecret("")
+	c.SetAuthMethod(AuthMethodPrivateKeyJWT)
+	return nil
+}
+
+// NewClientAssertion method will create a client assertion JWT for
+// the ClientID (RFC 7523, section 2.2), signed with its private key.
+// The assertion is issued by (and about) the Client ID, is valid for
+// a few minutes, and has a unique ID so it can't be replayed
+func (c *ClientID) NewClientAssertion(audience string) (string, error) {
+	if c.AssertionKey == "" {
+		return "", errors.New(`No private key set for the private_key_jwt client authentication`)
+	}
+
+	jti, err := randomString(clientAssertionIDLength)
+	if err != nil {
+		return "", err
+	}
+
+	jwt := &JWT{
+		Claim: &JWTClaim{},
+	}
+
+	jwt.InitHeader()
+	if c.AssertionKeyID != "" {
+		if err := jwt.SetKeyID(c.AssertionKeyID); err != nil {
+			return "", err
+		}
+	}
+
+	jwt.Claim.SetIssuer(c.GetID())
+	jwt.Claim.SetSubscriber(c.GetID())
+	jwt.Claim.SetAudience(audience)
+	jwt.Claim.SetJWTID(jti)
+	jwt.Claim.SetLifetime(clientAssertionLifetime)
+
+	if jwt.Signature, err = jwt.Sign(c.AssertionKey); err != nil {
+		return "", err
+	}
+
+	if jwt.Output, err = jwt.Build(); err != nil {
+		return "", err
+	}
+
+	return jwt.GetOutput(), nil
+}
@@ -0,0 +1,100 @@
+package oauth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClientAssertion(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemKey := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+
+	tests := []struct {
+		key   string
+		keyID string
+		ok    bool
+	}{
+		{
+			key:   pemKey,
+			keyID: "SomeKeyID",
+			ok:    true,
+		}, {
+			key: pemKey,
+			ok:  true,
+		}, {
+			key: "not a key",
+			ok:  false,
+		},
+	}
+
+	for _, test := range tests {
+		clientID, _ := NewClientID("ClientID", "ClientSecret", "openid", "")
+
+		if err := clientID.SetAssertionKey(test.key, test.keyID); (err == nil) != test.ok {
+			t.Errorf(`TestNewClientAssertion(%q) = %v, expected error to be %v`, test.keyID, err, !test.ok)
+			continue
+		} else if err != nil {
+			continue
+		}
+
+		if clientID.GetSecret() != "" || clientID.GetAuthMethod() != AuthMethodPrivateKeyJWT {
+			t.Errorf(`TestNewClientAssertion(%q) expected the secret to be dropped and the auth method to be %q`, test.keyID, AuthMethodPrivateKeyJWT)
+		}
+
+		assertion, err := clientID.NewClientAssertion(GoogleTokenURL)
+		if err != nil {
+			t.Errorf(`TestNewClientAssertion(%q) = %v, expected no error`, test.keyID, err)
+			continue
+		}
+
+		parts := strings.Split(assertion, ".")
+		if len(parts) != 3 {
+			t.Errorf(`TestNewClientAssertion(%q) = %q, expected a JWT with 3 parts`, test.keyID, assertion)
+			continue
+		}
+
+		header := &jwtHeaderKeyID{}
+		claim := &JWTClaim{}
+		for i, v := range []interface{}{header, claim} {
+			buf, err := base64.RawURLEncoding.DecodeString(parts[i])
+			if err != nil {
+				t.Fatalf(`TestNewClientAssertion(%q) failed to decode part %d: %v`, test.keyID, i, err)
+			}
+			if err := json.Unmarshal(buf, v); err != nil {
+				t.Fatalf(`TestNewClientAssertion(%q) failed to unmarshal part %d: %v`, test.keyID, i, err)
+			}
+		}
+
+		if header.KeyID != test.keyID {
+			t.Errorf(`TestNewClientAssertion(%q) = %q, expected kid to be %q`, test.keyID, header.KeyID, test.keyID)
+		}
+		if claim.Issuer != "ClientID" || claim.Subscriber != "ClientID" || claim.Audience != GoogleTokenURL || claim.JWTID == "" {
+			t.Errorf(`TestNewClientAssertion(%q) = %+v, unexpected claims`, test.keyID, claim)
+		}
+		if time.Unix(claim.Expiry, 0).After(time.Now().Add(clientAssertionLifetime)) {
+			t.Errorf(`TestNewClientAssertion(%q) expiry = %d, expected at most %v from now`, test.keyID, claim.Expiry, clientAssertionLifetime)
+		}
+
+		sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hash[:], sig); err != nil {
+			t.Errorf(`TestNewClientAssertion(%q) failed signature verification: %v`, test.keyID, err)
+		}
+	}
+}
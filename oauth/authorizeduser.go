@@ -0,0 +1,116 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+)
+
+// AuthorizedUser struct represents an authorized_user credentials
+// file, as created by gcloud for Application Default Credentials,
+// holding a Client ID and a user's Refresh Token
+type AuthorizedUser struct {
+	Type           string `json:"type"`
+	ClientID       string `json:"client_id"`
+	ClientSecret   string `json:"client_secret"`
+	RefreshToken   string `json:"refresh_token"`
+	QuotaProjectID string `json:"quota_project_id,omitempty"`
+}
+
+// ReadAuthorizedUser function will read and validate the input
+// authorized_user credentials file
+func ReadAuthorizedUser(file string) (*AuthorizedUser, error) {
+	f, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &AuthorizedUser{}
+	if err := json.Unmarshal(f, user); err != nil {
+		return nil, errors.New(`Invalid authorized_user file ` + file + `: ` + err.Error())
+	}
+
+	if user.Type != CredentialTypeAuthorizedUser {
+		return nil, errors.New(`Invalid credential type: ` + user.Type + ` - expected ` + CredentialTypeAuthorizedUser)
+	}
+	if user.RefreshToken == "" {
+		return nil, errors.New(`Refresh Token not defined in ` + file + ` - mandatory field`)
+	}
+
+	return user, nil
+}
+
+// NewClientIDFromAuthorizedUser function will generate a Client ID
+// based on the input authorized_user credentials file, with its
+// Refresh Token set. If a Refresh Token is provided, it takes
+// precedence over the file's; otherwise the file is also set as the
+// Client ID's Store, so that a rotated Refresh Token is written back
+func NewClientIDFromAuthorizedUser(file, scopes, refreshToken string) (*ClientID, error) {
+	user, err := ReadAuthorizedUser(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var store TokenStore
+	if refreshToken == "" {
+		refreshToken = user.RefreshToken
+		store = NewAuthorizedUserStore(file)
+	}
+
+	client, err := NewClientID(user.ClientID, user.ClientSecret, scopes, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	client.Store = store
+	return client, nil
+}
+
+// AuthorizedUserStore struct represents the Refresh Token held in an
+// authorized_user credentials file
+type AuthorizedUserStore struct {
+	Path string
+}
+
+// NewAuthorizedUserStore function will create an AuthorizedUserStore
+// for the input path
+func NewAuthorizedUserStore(path string) *AuthorizedUserStore {
+	return &AuthorizedUserStore{
+		Path: path,
+	}
+}
+
+// Load method returns the Refresh Token stored in the
+// AuthorizedUserStore's file
+func (a *AuthorizedUserStore) Load() (string, error) {
+	user, err := ReadAuthorizedUser(a.Path)
+	if err != nil {
+		return "", err
+	}
+	return user.RefreshToken, nil
+}
+
+// Save method will (atomically) replace the refresh_token field in the
+// AuthorizedUserStore's file, keeping the remaining fields as they are
+func (a *AuthorizedUserStore) Save(token string) error {
+	f, err := ioutil.ReadFile(a.Path)
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(f, &fields); err != nil {
+		return errors.New(`Invalid authorized_user file ` + a.Path + `: ` + err.Error())
+	}
+
+	if fields["refresh_token"], err = json.Marshal(token); err != nil {
+		return err
+	}
+
+	buf, err := json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(a.Path, append(buf, '\n'))
+}
@@ -0,0 +1,110 @@
+package oauth
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewClientIDFromAuthorizedUser(t *testing.T) {
+	tests := []struct {
+		content string
+		refresh string
+		want    string
+		ok      bool
+	}{
+		{
+			content: `{"type":"authorized_user","client_id":"ClientID","client_secret":"ClientSecret","refresh_token":"RefreshToken"}`,
+			want:    "RefreshToken",
+			ok:      true,
+		}, {
+			content: `{"type":"authorized_user","client_id":"ClientID","client_secret":"ClientSecret","refresh_token":"RefreshToken"}`,
+			refresh: "OtherRefreshToken",
+			want:    "OtherRefreshToken",
+			ok:      true,
+		}, {
+			content: `{"type":"authorized_user","client_id":"ClientID","client_secret":"ClientSecret"}`,
+			ok:      false,
+		}, {
+			content: `{"type":"authorized_user","client_secret":"ClientSecret","refresh_token":"RefreshToken"}`,
+			ok:      false,
+		}, {
+			content: `{"type":"service_account","client_id":"1234567890"}`,
+			ok:      false,
+		},
+	}
+
+	dir := t.TempDir()
+
+	for _, test := range tests {
+		file := filepath.Join(dir, "application_default_credentials.json")
+		if err := ioutil.WriteFile(file, []byte(test.content), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		clientID, err := NewClientIDFromAuthorizedUser(file, "", test.refresh)
+		if (err == nil) != test.ok {
+			t.Errorf(`TestNewClientIDFromAuthorizedUser(%q) = %v, expected error to be %v`, test.content, err, !test.ok)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		if clientID.GetID() != "ClientID" || clientID.GetSecret() != "ClientSecret" {
+			t.Errorf(`TestNewClientIDFromAuthorizedUser(%q) = %q, expected Client ID to be set from the file`, test.content, clientID.GetID())
+		}
+		if clientID.RefreshToken.GetToken() != test.want {
+			t.Errorf(`TestNewClientIDFromAuthorizedUser(%q) = %q, expected Refresh Token to be %q`, test.content, clientID.RefreshToken.GetToken(), test.want)
+		}
+
+		// client_secret.json files' constructor also accepts them
+		if clientID, err = NewClientIDFromFile(file, "", test.refresh); err != nil || clientID.RefreshToken.GetToken() != test.want {
+			t.Errorf(`TestNewClientIDFromAuthorizedUser(%q) = %v, expected NewClientIDFromFile to accept authorized_user files`, test.content, err)
+		}
+	}
+}
+
+func TestAuthorizedUserStore(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "application_default_credentials.json")
+	content := `{"type":"authorized_user","client_id":"ClientID","client_secret":"ClientSecret","refresh_token":"RefreshToken","quota_project_id":"SomeProject"}`
+	if err := ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// a Refresh Token provided separately isn't written to the file
+	clientID, err := NewClientIDFromAuthorizedUser(file, "", "OtherRefreshToken")
+	if err != nil {
+		t.Fatalf(`TestAuthorizedUserStore() = %v, expected no error`, err)
+	}
+	if clientID.Store != nil {
+		t.Errorf(`TestAuthorizedUserStore() = %v, expected no Store with a provided Refresh Token`, clientID.Store)
+	}
+
+	if clientID, err = NewClientIDFromAuthorizedUser(file, "", ""); err != nil {
+		t.Fatalf(`TestAuthorizedUserStore() = %v, expected no error`, err)
+	}
+	if err := clientID.SetToken([]byte(`{"access_token":"SomeAccessToken","expires_in":3599,"refresh_token":"NewRefreshToken"}`)); err != nil {
+		t.Fatalf(`TestAuthorizedUserStore() = %v, expected no error`, err)
+	}
+
+	user, err := ReadAuthorizedUser(file)
+	if err != nil {
+		t.Fatalf(`TestAuthorizedUserStore() = %v, expected the file to remain valid`, err)
+	}
+	if user.RefreshToken != "NewRefreshToken" {
+		t.Errorf(`TestAuthorizedUserStore() = %q, expected Refresh Token to be %q`, user.RefreshToken, "NewRefreshToken")
+	}
+
+	// the remaining fields are kept as they are
+	fields := map[string]string{}
+	buf, _ := ioutil.ReadFile(file)
+	if err := json.Unmarshal(buf, &fields); err != nil || fields["quota_project_id"] != "SomeProject" || fields["client_secret"] != "ClientSecret" {
+		t.Errorf(`TestAuthorizedUserStore() = %v, expected the remaining fields to be kept`, fields)
+	}
+
+	if _, err := NewAuthorizedUserStore(filepath.Join(t.TempDir(), "missing.json")).Load(); err == nil {
+		t.Errorf(`TestAuthorizedUserStore() = missing file succeeded, expected an error`)
+	}
+}
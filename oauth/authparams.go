@@ -0,0 +1,95 @@
+package oauth
+
+import (
+	"net/url"
+)
+
+const (
+	// AccessTypeOffline requests a Refresh Token along with the
+	// Access Token (default)
+	AccessTypeOffline string = `offline`
+
+	// AccessTypeOnline requests an Access Token only
+	AccessTypeOnline string = `online`
+
+	// PromptConsent forces the consent screen to be shown (default),
+	// so that a Refresh Token is always issued
+	PromptConsent string = `consent`
+)
+
+// AuthParams struct represents the optional parameters of an
+// authorization request, which change how the consent screen behaves
+// and what is granted. Empty values are omitted from the request
+type AuthParams struct {
+	AccessType           string
+	Prompt               string
+	LoginHint            string
+	HostedDomain         string
+	IncludeGrantedScopes bool
+}
+
+// NewAuthParams function returns the default AuthParams, requesting
+// offline access with a forced consent screen
+func NewAuthParams() *AuthParams {
+	return &AuthParams{
+		AccessType: AccessTypeOffline,
+		Prompt:     PromptConsent,
+	}
+}
+
+// SetLoginHint method will define the email address (or sub) of the
+// account to preselect in the consent screen
+func (p *AuthParams) SetLoginHint(input string) {
+	p.LoginHint = input
+	return
+}
+
+// SetHostedDomain method will define the Workspace domain the
+// consent screen is restricted to
+func (p *AuthParams) SetHostedDomain(input string) {
+	p.HostedDomain = input
+	return
+}
+
+// SetPrompt method will define the space-delimited list of prompts
+// to present the user with (none, consent, select_account)
+func (p *AuthParams) SetPrompt(input string) {
+	p.Prompt = input
+	return
+}
+
+// SetAccessType method will define whether a Refresh Token is
+// requested (offline) or not (online)
+func (p *AuthParams) SetAccessType(input string) {
+	p.AccessType = input
+	return
+}
+
+// SetIncludeGrantedScopes method will define whether previously
+// granted scopes are included in the new grant (incremental
+// authorization)
+func (p *AuthParams) SetIncludeGrantedScopes(input bool) {
+	p.IncludeGrantedScopes = input
+	return
+}
+
+// Apply method will add the AuthParams' set values to the input
+// query values
+func (p *AuthParams) Apply(query url.Values) {
+	if p.AccessType != "" {
+		query.Set("access_type", p.AccessType)
+	}
+	if p.Prompt != "" {
+		query.Set("prompt", p.Prompt)
+	}
+	if p.LoginHint != "" {
+		query.Set("login_hint", p.LoginHint)
+	}
+	if p.HostedDomain != "" {
+		query.Set("hd", p.HostedDomain)
+	}
+	if p.IncludeGrantedScopes {
+		query.Set("include_granted_scopes", "true")
+	}
+	return
+}
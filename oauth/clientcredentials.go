@@ -0,0 +1,71 @@
+package oauth
+
+import (
+	"errors"
+	"net/url"
+)
+
+// NewClientCredentials function will generate a Client ID for the
+// Client Credentials grant (RFC 6749, section 4.4), based on the
+// input parameters provided. Unlike NewClientID, scopes are optional,
+// as some providers rely on an audience instead
+func NewClientCredentials(id, secret, scopes string) (*ClientID, error) {
+	if id == "" {
+		return nil, errors.New(`Client ID not defined - mandatory field`)
+	}
+	if secret == "" {
+		return nil, errors.New(`Client Secret not defined - mandatory field`)
+	}
+
+	return newClientID("client_credentials", id, secret, scopes), nil
+}
+
+// NewClientCredentialsFromFile function will generate a Client ID for
+// the Client Credentials grant based on the input client_secret.json
+// file, setting its token endpoint from it
+func NewClientCredentialsFromFile(file, scopes string) (*ClientID, error) {
+	secretFile, err := ReadClientSecret(file)
+	if err != nil {
+		return nil, err
+	}
+	secret := secretFile.Get()
+
+	client, err := NewClientCredentials(secret.ClientID, secret.ClientSecret, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	client.SetEndpoint(&Endpoint{
+		TokenURL: secret.TokenURI,
+	})
+
+	return client, nil
+}
+
+// ClientCredentials method will request an Access Token for the
+// ClientID itself (machine-to-machine), with the Client Credentials
+// grant. The audience is optional, and is sent for providers which
+// require it to select the target API (like Auth0)
+func (c *ClientID) ClientCredentials(audience string) error {
+	form := url.Values{
+		"grant_type": {`client_credentials`},
+	}
+
+	if c.GetScopes() != "" {
+		form.Set("scope", c.GetScopes())
+	}
+	if audience != "" {
+		form.Set("audience", audience)
+	}
+
+	body, err := c.PostForm(c.RefreshToken.GetTokenURL(), form)
+	if err != nil {
+		return err
+	}
+
+	if err := ParseTokenError(body); err != nil {
+		return err
+	}
+
+	return c.SetToken(body)
+}
@@ -0,0 +1,151 @@
+package oauth
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+)
+
+func TestClientIDClientCredentials(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+
+		if r.PostForm.Get("client_id") != "ClientID" || r.PostForm.Get("client_secret") != "ClientSecret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"SomeAccessToken","expires_in":3599,"token_type":"Bearer"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		scopes   string
+		audience string
+		ok       bool
+	}{
+		{
+			scopes: "read write",
+			ok:     true,
+		}, {
+			audience: "https://api.example.com",
+			ok:       true,
+		}, {
+			scopes:   "read",
+			audience: "https://api.example.com",
+			ok:       true,
+		}, {
+			ok: true,
+		},
+	}
+
+	for _, test := range tests {
+		clientID, err := NewClientCredentials("ClientID", "ClientSecret", test.scopes)
+		if err != nil {
+			t.Errorf(`TestClientIDClientCredentials(%q, %q) = %v, expected no error`, test.scopes, test.audience, err)
+			continue
+		}
+		clientID.RefreshToken.SetTokenURL(server.URL)
+
+		err = clientID.ClientCredentials(test.audience)
+		if (err == nil) != test.ok {
+			t.Errorf(`TestClientIDClientCredentials(%q, %q) = %v, expected error to be %v`, test.scopes, test.audience, err, !test.ok)
+			continue
+		}
+
+		if form.Get("grant_type") != "client_credentials" {
+			t.Errorf(`TestClientIDClientCredentials(%q, %q) = %q, expected grant_type to be client_credentials`, test.scopes, test.audience, form.Get("grant_type"))
+		}
+
+		// scope and audience are only sent when set
+		if _, ok := form["scope"]; ok != (test.scopes != "") || form.Get("scope") != test.scopes {
+			t.Errorf(`TestClientIDClientCredentials(%q, %q) = %v, unexpected scope parameter`, test.scopes, test.audience, form)
+		}
+		if _, ok := form["audience"]; ok != (test.audience != "") || form.Get("audience") != test.audience {
+			t.Errorf(`TestClientIDClientCredentials(%q, %q) = %v, unexpected audience parameter`, test.scopes, test.audience, form)
+		}
+
+		if clientID.AccessToken.Token != "SomeAccessToken" {
+			t.Errorf(`TestClientIDClientCredentials(%q, %q) = %q, expected result to be %q`, test.scopes, test.audience, clientID.AccessToken.Token, "SomeAccessToken")
+		}
+	}
+}
+
+func TestNewClientCredentials(t *testing.T) {
+	tests := []struct {
+		id     string
+		secret string
+		scopes string
+		ok     bool
+	}{
+		{
+			id:     "ClientID",
+			secret: "ClientSecret",
+			scopes: "read",
+			ok:     true,
+		}, {
+			id:     "ClientID",
+			secret: "ClientSecret",
+			ok:     true,
+		}, {
+			secret: "ClientSecret",
+			ok:     false,
+		}, {
+			id: "ClientID",
+			ok: false,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := NewClientCredentials(test.id, test.secret, test.scopes)
+		if (err == nil) != test.ok {
+			t.Errorf(`TestNewClientCredentials(%q, %q, %q) = %v, expected error to be %v`, test.id, test.secret, test.scopes, err, !test.ok)
+		}
+	}
+
+	// unlike the Client Credentials grant, the authorization code
+	// grant requires scopes when no Refresh Token is set
+	if _, err := NewClientID("ClientID", "ClientSecret", "", ""); err == nil {
+		t.Errorf(`TestNewClientCredentials() = NewClientID without scopes succeeded, expected an error`)
+	}
+}
+
+func TestNewClientCredentialsFromFile(t *testing.T) {
+	var grantType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		grantType = r.FormValue("grant_type")
+		w.Write([]byte(`{"access_token":"SomeAccessToken","expires_in":3599,"token_type":"Bearer"}`))
+	}))
+	defer server.Close()
+
+	file := filepath.Join(t.TempDir(), "client_secret.json")
+	content := `{"web":{"client_id":"ClientID","client_secret":"ClientSecret","auth_uri":"https://accounts.example.com/auth","token_uri":"` + server.URL + `","redirect_uris":["https://app.example.com/callback"]}}`
+	if err := ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	clientID, err := NewClientCredentialsFromFile(file, "")
+	if err != nil {
+		t.Fatalf(`TestNewClientCredentialsFromFile() = %v, expected no error`, err)
+	}
+
+	if clientID.RefreshToken.GetTokenURL() != server.URL {
+		t.Errorf(`TestNewClientCredentialsFromFile() = %q, expected token URL to be %q`, clientID.RefreshToken.GetTokenURL(), server.URL)
+	}
+
+	if err := clientID.ClientCredentials(""); err != nil {
+		t.Errorf(`TestNewClientCredentialsFromFile() = %v, expected no error`, err)
+	}
+	if grantType != "client_credentials" {
+		t.Errorf(`TestNewClientCredentialsFromFile() = %q, expected the request to reach the file's token_uri`, grantType)
+	}
+
+	if _, err := NewClientCredentialsFromFile(filepath.Join(t.TempDir(), "missing.json"), ""); err == nil {
+		t.Errorf(`TestNewClientCredentialsFromFile() = missing file succeeded, expected an error`)
+	}
+}
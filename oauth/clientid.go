@@ -2,26 +2,38 @@ package oauth
 
 import (
 	"bufio"
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"net/url"
 	"os"
 	"strings"
+	"time"
+)
 
+const (
+	oobRedirectURI string = `urn:ietf:wg:oauth:2.0:oob`
 )
 
 // ClientID struct will represent a Client ID object
 type ClientID struct {
-	Type         string
-	ID           string
-	Secret       string
-	Scopes       string
-	RefreshToken *RefreshToken
-	AccessToken  *AccessToken
+	Type           string
+	ID             string
+	Secret         string
+	Scopes         string
+	RedirectURI    string
+	PKCEMethod     string
+	PKCE           *PKCE
+	State          string
+	Nonce          string
+	Endpoint       *Endpoint
+	AuthMethod     string
+	AssertionKey   string
+	AssertionKeyID string
+	Store          TokenStore
+	AuthParams     *AuthParams
+	RefreshToken   *RefreshToken
+	AccessToken    *AccessToken
 }
 
 // RefreshToken struct will represent a Refresh Token object
@@ -29,16 +41,15 @@ type RefreshToken struct {
 	AccessCode string
 	AuthURL    string
 	TokenURL   string
+	DeviceURL  string
+	RevokeURL  string
 	Token      string
+	Rotated    bool
 }
 
 // NewClientID function will generate a Client ID based on
 // the input parameters provided
 func NewClientID(id, secret, scopes, refreshToken string) (*ClientID, error) {
-	client := &ClientID{
-		Type: "client_id",
-	}
-
 	if id == "" {
 		return nil, errors.New(`Client ID not defined - mandatory field`)
 	}
@@ -49,10 +60,7 @@ func NewClientID(id, secret, scopes, refreshToken string) (*ClientID, error) {
 		return nil, errors.New(`Scopes not defined - mandatory field when Refresh Token is absent`)
 	}
 
-	client.SetID(id)
-	client.SetSecret(secret)
-	client.SetScopes(scopes)
-	client.InitToken()
+	client := newClientID("client_id", id, secret, scopes)
 
 	if refreshToken != "" {
 		client.RefreshToken.SetToken(refreshToken)
@@ -62,12 +70,52 @@ func NewClientID(id, secret, scopes, refreshToken string) (*ClientID, error) {
 	return client, nil
 }
 
+// NewPublicClientID function will generate a Client ID without a
+// Client Secret, for public clients (RFC 6749, section 2.1) and for
+// clients authenticated with a private key (private_key_jwt). Scopes
+// are optional, as they aren't needed to revoke or introspect tokens
+func NewPublicClientID(id, scopes string) (*ClientID, error) {
+	if id == "" {
+		return nil, errors.New(`Client ID not defined - mandatory field`)
+	}
+
+	client := newClientID("client_id", id, "", scopes)
+	client.SetAuthMethod(AuthMethodNone)
+
+	return client, nil
+}
+
+func newClientID(clientType, id, secret, scopes string) *ClientID {
+	client := &ClientID{
+		Type: clientType,
+	}
+
+	client.SetID(id)
+	client.SetSecret(secret)
+	client.SetScopes(scopes)
+	client.SetRedirectURI(oobRedirectURI)
+	client.SetPKCEMethod(PKCEMethodS256)
+	client.SetAuthMethod(AuthMethodClientSecretPost)
+	client.AuthParams = NewAuthParams()
+	client.Endpoint = GoogleEndpoint()
+	client.InitToken()
+
+	return client
+}
+
 // Gen method will initiate the process of creating an Access Code
 // (by having the user visiting an authorization page), and with doing
 // so creating a Refresh Token for this request
 func (c *ClientID) Gen() {
+	if err := c.InitPKCE(); err != nil {
+		panic(err)
+	}
+	if err := c.InitState(); err != nil {
+		panic(err)
+	}
+
 	c.RefreshToken.SetAuthURL(c)
-	fmt.Println(`Please visit the following URL and paste the Access code below: 
+	fmt.Println(`Please visit the following URL and paste the Access code (or the full redirect URL) below: 
 ===
 ` + c.RefreshToken.GetAuthURL() + `
 ===
@@ -77,82 +125,131 @@ Access Code:	`)
 	accessCode, _ := reader.ReadString('\n')
 	// convert CRLF to LF
 	accessCode = strings.Replace(accessCode, "\n", "", -1)
-	c.RefreshToken.SetAccessCode(accessCode)
-
-	post, err := json.Marshal(map[string]string{
-		"code":          c.RefreshToken.GetAccessCode(),
-		"client_id":     c.GetID(),
-		"client_secret": c.GetSecret(),
-		"redirect_uri":  `urn:ietf:wg:oauth:2.0:oob`,
-		"grant_type":    `authorization_code`,
-	})
 
+	accessCode, err := ParseRedirect(accessCode, c.GetState())
 	if err != nil {
 		panic(err)
 	}
 
-	postBytes := bytes.NewBuffer(post)
+	if err := c.Exchange(accessCode); err != nil {
+		panic(err)
+	}
+	return
+}
+
+// GenLoopback method will initiate the process of creating an
+// Access Code through a temporary HTTP listener on the loopback
+// interface, instead of having the user paste the code in the
+// terminal. The listener is bound to the input port (0 for a random
+// one), and will wait for the redirect until the input timeout
+// is reached
+func (c *ClientID) GenLoopback(port int, timeout time.Duration) error {
+	if err := c.InitPKCE(); err != nil {
+		return err
+	}
+	if err := c.InitState(); err != nil {
+		return err
+	}
 
-	resp, err := http.Post(c.RefreshToken.TokenURL, "application/json", postBytes)
+	loopback, err := NewLoopback(port, timeout)
+	if err != nil {
+		return err
+	}
+	loopback.State = c.GetState()
+
+	c.SetRedirectURI(loopback.GetRedirectURI())
+	c.RefreshToken.SetAuthURL(c)
+	fmt.Println(`Please visit the following URL to grant access to the app: 
+===
+` + c.RefreshToken.GetAuthURL() + `
+===
+Waiting for the authorization redirect on ` + loopback.GetRedirectURI() + ` ...`)
 
+	accessCode, err := loopback.Wait()
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	defer resp.Body.Close()
+	return c.Exchange(accessCode)
+}
 
-	body, err := ioutil.ReadAll(resp.Body)
+// Exchange method will request the Access and Refresh Tokens from
+// the token endpoint, with the input Access Code
+func (c *ClientID) Exchange(accessCode string) error {
+	c.RefreshToken.SetAccessCode(accessCode)
+
+	form := url.Values{
+		"code":         {c.RefreshToken.GetAccessCode()},
+		"redirect_uri": {c.GetRedirectURI()},
+		"grant_type":   {`authorization_code`},
+	}
 
+	if c.PKCE != nil {
+		form.Set("code_verifier", c.PKCE.GetVerifier())
+	}
+
+	body, err := c.PostForm(c.RefreshToken.TokenURL, form)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	c.SetToken(body)
-	return
+	if err := ParseTokenError(body); err != nil {
+		return err
+	}
+
+	return c.SetToken(body)
 }
 
 // SetToken method will define the token values for Client IDs;
-// setting the Access Token and Refresh Token values respectively
-func (c *ClientID) SetToken(body []byte) {
+// setting the Access Token and Refresh Token values respectively.
+// The Refresh Token is only replaced when a new one is returned, as
+// servers which don't rotate Refresh Tokens omit it on refresh. When
+// it is replaced, it is written back to the ClientID's Store (if set),
+// returning an error if it can't be persisted. In that case the new
+// Refresh Token is printed to stderr, as the previous one may already
+// be invalidated
+func (c *ClientID) SetToken(body []byte) error {
+	*c.AccessToken = AccessToken{}
+	c.RefreshToken.Rotated = false
 	if err := json.Unmarshal(body, c.AccessToken); err != nil {
-		panic(err)
+		return err
+	}
+
+	token := c.AccessToken.RefreshToken
+	if token == "" || token == c.RefreshToken.GetToken() {
+		return nil
 	}
-	c.RefreshToken.Token = c.AccessToken.RefreshToken
+
+	c.RefreshToken.Rotated = c.RefreshToken.HasToken()
+	c.RefreshToken.SetToken(token)
+
+	if c.Store != nil {
+		if err := c.Store.Save(token); err != nil {
+			fmt.Fprintln(os.Stderr, `New Refresh Token: `+token)
+			return errors.New(`Unable to persist the new Refresh Token: ` + err.Error())
+		}
+	}
+	return nil
 }
 
 // Refresh method will create a new Access Token based on a valid
 // combination of credentials and refresh token values
 func (c *ClientID) Refresh() {
 	if c.RefreshToken.HasToken() {
-		post, err := json.Marshal(map[string]string{
-			"client_id":     c.GetID(),
-			"client_secret": c.GetSecret(),
-			"refresh_token": c.RefreshToken.GetToken(),
-			"grant_type":    `refresh_token`,
+		body, err := c.PostForm(c.RefreshToken.TokenURL, url.Values{
+			"refresh_token": {c.RefreshToken.GetToken()},
+			"grant_type":    {`refresh_token`},
 		})
 
 		if err != nil {
 			panic(err)
 		}
 
-		postBytes := bytes.NewBuffer(post)
-
-		resp, err := http.Post(c.RefreshToken.TokenURL, "application/json", postBytes)
+		CheckResponse(body)
 
-		if err != nil {
+		if err := c.SetToken(body); err != nil {
 			panic(err)
 		}
-
-		defer resp.Body.Close()
-
-		body, err := ioutil.ReadAll(resp.Body)
-
-		if err != nil {
-			panic(err)
-		}
-
-		json.Unmarshal(body, c.AccessToken)
-		c.RefreshToken.Token = c.AccessToken.RefreshToken
 		return
 	}
 	c.Gen()
@@ -180,12 +277,83 @@ func (c *ClientID) SetScopes(input string) {
 	return
 }
 
+// SetRedirectURI method will define the redirect URI for the
+// ClientID object
+func (c *ClientID) SetRedirectURI(input string) {
+	c.RedirectURI = input
+	return
+}
+
+// SetAuthMethod method will define the client authentication method
+// used by the ClientID object on token requests (client_secret_post,
+// client_secret_basic or none)
+func (c *ClientID) SetAuthMethod(input string) {
+	c.AuthMethod = input
+	return
+}
+
+// SetPKCEMethod method will define the PKCE code challenge method
+// for the ClientID object (either S256 or plain)
+func (c *ClientID) SetPKCEMethod(input string) {
+	c.PKCEMethod = input
+	return
+}
+
+// InitPKCE method will generate a new PKCE verifier and challenge
+// for the ClientID object. This is done on every authorization
+// request, so that a verifier is never reused
+func (c *ClientID) InitPKCE() error {
+	pkce, err := NewPKCE(c.PKCEMethod)
+	if err != nil {
+		return err
+	}
+
+	c.PKCE = pkce
+	return nil
+}
+
+// InitState method will generate a new random state for the
+// ClientID object, used to protect the authorization request against
+// CSRF. If the openid scope is requested, a nonce is also generated
+func (c *ClientID) InitState() error {
+	state, err := randomString(stateLength)
+	if err != nil {
+		return err
+	}
+	c.State = state
+
+	c.Nonce = ""
+	if hasScope(c.Scopes, "openid") {
+		nonce, err := randomString(nonceLength)
+		if err != nil {
+			return err
+		}
+		c.Nonce = nonce
+	}
+
+	return nil
+}
+
 // InitToken method initiates the tokens in a Client ID, so that
 // their methods can be accessed later on
 func (c *ClientID) InitToken() {
 	c.RefreshToken = &RefreshToken{}
 	c.AccessToken = &AccessToken{}
-	c.RefreshToken.SetTokenURL()
+	c.RefreshToken.SetTokenURL(c.Endpoint.TokenURL)
+	c.RefreshToken.SetDeviceURL(c.Endpoint.DeviceURL)
+	c.RefreshToken.SetRevokeURL(c.Endpoint.RevokeURL)
+	return
+}
+
+// SetEndpoint method will define the authorization server's URLs for
+// the ClientID object. URLs not set in the input Endpoint keep their
+// current value (Google's, by default)
+func (c *ClientID) SetEndpoint(input *Endpoint) {
+	c.Endpoint.Merge(input)
+
+	c.RefreshToken.SetTokenURL(c.Endpoint.TokenURL)
+	c.RefreshToken.SetDeviceURL(c.Endpoint.DeviceURL)
+	c.RefreshToken.SetRevokeURL(c.Endpoint.RevokeURL)
 	return
 }
 
@@ -205,6 +373,35 @@ func (c *ClientID) GetScopes() string {
 	return c.Scopes
 }
 
+// GetAuthMethod method returns the client authentication method from
+// a ClientID object
+func (c *ClientID) GetAuthMethod() string {
+	return c.AuthMethod
+}
+
+// GetPKCEMethod method returns the PKCE code challenge method from
+// a ClientID object
+func (c *ClientID) GetPKCEMethod() string {
+	return c.PKCEMethod
+}
+
+// GetState method returns the state value from a ClientID object
+func (c *ClientID) GetState() string {
+	return c.State
+}
+
+// GetNonce method returns the OIDC nonce value from a ClientID
+// object
+func (c *ClientID) GetNonce() string {
+	return c.Nonce
+}
+
+// GetRedirectURI method returns the redirect URI from a ClientID
+// object
+func (c *ClientID) GetRedirectURI() string {
+	return c.RedirectURI
+}
+
 // SetAccessCode method will define the access code for the
 // RefreshToken object
 func (r *RefreshToken) SetAccessCode(input string) {
@@ -215,15 +412,54 @@ func (r *RefreshToken) SetAccessCode(input string) {
 // SetAuthURL method  will define the auth URL for the RefreshToken
 // object
 func (r *RefreshToken) SetAuthURL(c *ClientID) {
-	scopes := url.QueryEscape(c.Scopes)
-	r.AuthURL = `https://accounts.google.com/o/oauth2/auth?client_id=` + c.ID + `&redirect_uri=urn%3Aietf%3Awg%3Aoauth%3A2.0%3Aoob&response_type=code&access_type=offline&prompt=consent&scope=` + scopes
+	authURL, err := url.Parse(c.Endpoint.AuthURL)
+	if err != nil {
+		panic(err)
+	}
+
+	query := authURL.Query()
+	query.Set("client_id", c.ID)
+	query.Set("redirect_uri", c.RedirectURI)
+	query.Set("response_type", "code")
+	query.Set("scope", c.Scopes)
+
+	if c.AuthParams != nil {
+		c.AuthParams.Apply(query)
+	}
+	if c.PKCE != nil {
+		query.Set("code_challenge", c.PKCE.GetChallenge())
+		query.Set("code_challenge_method", c.PKCE.GetMethod())
+	}
+	if c.State != "" {
+		query.Set("state", c.State)
+	}
+	if c.Nonce != "" {
+		query.Set("nonce", c.Nonce)
+	}
+
+	authURL.RawQuery = query.Encode()
+	r.AuthURL = authURL.String()
 	return
 }
 
 // SetTokenURL method will define the token URL for the
 // RefreshToken object
-func (r *RefreshToken) SetTokenURL() {
-	r.TokenURL = `https://accounts.google.com/o/oauth2/token`
+func (r *RefreshToken) SetTokenURL(input string) {
+	r.TokenURL = input
+	return
+}
+
+// SetDeviceURL method will define the device authorization URL
+// for the RefreshToken object
+func (r *RefreshToken) SetDeviceURL(input string) {
+	r.DeviceURL = input
+	return
+}
+
+// SetRevokeURL method will define the token revocation URL for the
+// RefreshToken object
+func (r *RefreshToken) SetRevokeURL(input string) {
+	r.RevokeURL = input
 	return
 }
 
@@ -252,12 +488,30 @@ func (r *RefreshToken) GetTokenURL() string {
 	return r.TokenURL
 }
 
+// GetDeviceURL method returns the device authorization URL from the
+// RefreshToken object
+func (r *RefreshToken) GetDeviceURL() string {
+	return r.DeviceURL
+}
+
+// GetRevokeURL method returns the token revocation URL from the
+// RefreshToken object
+func (r *RefreshToken) GetRevokeURL() string {
+	return r.RevokeURL
+}
+
 // GetToken method returns the Refresh Token from the RefreshToken
 // object
 func (r *RefreshToken) GetToken() string {
 	return r.Token
 }
 
+// IsRotated method checks whether the Refresh Token was replaced by
+// a new one on the last token request, returning a boolean
+func (r *RefreshToken) IsRotated() bool {
+	return r.Rotated
+}
+
 // HasToken method check whether the Refresh Token value is set
 // from a RefreshToken object, returning a boolean
 func (r *RefreshToken) HasToken() bool {
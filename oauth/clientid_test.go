@@ -1,9 +1,10 @@
 package oauth
 
 import (
+	"path/filepath"
+	"strings"
 	"testing"
 	// "fmt"
-	// "strings"
 )
 
 func TestNewClientID(t *testing.T) {
@@ -101,7 +102,7 @@ func TestClientIDAuthURL(t *testing.T) {
 				scopes:       "https://www.googleapis.com/auth/userinfo.email",
 				refreshToken: "",
 			},
-			want: "https://accounts.google.com/o/oauth2/auth?client_id=ClientID&redirect_uri=urn%3Aietf%3Awg%3Aoauth%3A2.0%3Aoob&response_type=code&access_type=offline&prompt=consent&scope=https%3A%2F%2Fwww.googleapis.com%2Fauth%2Fuserinfo.email",
+			want: "https://accounts.google.com/o/oauth2/auth?access_type=offline&client_id=ClientID&prompt=consent&redirect_uri=urn%3Aietf%3Awg%3Aoauth%3A2.0%3Aoob&response_type=code&scope=https%3A%2F%2Fwww.googleapis.com%2Fauth%2Fuserinfo.email",
 		},{
 			input: input{
 				id:           "ClientID",
@@ -109,7 +110,7 @@ func TestClientIDAuthURL(t *testing.T) {
 				scopes:       "https://www.googleapis.com/auth/userinfo.email https://www.googleapis.com/auth/userinfo.profile",
 				refreshToken: "",
 			},
-			want: "https://accounts.google.com/o/oauth2/auth?client_id=ClientID&redirect_uri=urn%3Aietf%3Awg%3Aoauth%3A2.0%3Aoob&response_type=code&access_type=offline&prompt=consent&scope=https%3A%2F%2Fwww.googleapis.com%2Fauth%2Fuserinfo.email+https%3A%2F%2Fwww.googleapis.com%2Fauth%2Fuserinfo.profile",
+			want: "https://accounts.google.com/o/oauth2/auth?access_type=offline&client_id=ClientID&prompt=consent&redirect_uri=urn%3Aietf%3Awg%3Aoauth%3A2.0%3Aoob&response_type=code&scope=https%3A%2F%2Fwww.googleapis.com%2Fauth%2Fuserinfo.email+https%3A%2F%2Fwww.googleapis.com%2Fauth%2Fuserinfo.profile",
 		},
 	}
 
@@ -127,3 +128,163 @@ func TestClientIDAuthURL(t *testing.T) {
 		}
 	}
 }
+
+func TestClientIDEndpoint(t *testing.T) {
+	tests := []struct {
+		input *Endpoint
+		want  *Endpoint
+	}{
+		{
+			input: nil,
+			want:  GoogleEndpoint(),
+		}, {
+			input: &Endpoint{
+				AuthURL:  "https://sso.example.com/auth",
+				TokenURL: "https://sso.example.com/token",
+			},
+			want: &Endpoint{
+				Issuer:      GoogleIssuer,
+				AuthURL:     "https://sso.example.com/auth",
+				TokenURL:    "https://sso.example.com/token",
+				RevokeURL:   GoogleRevokeURL,
+				DeviceURL:   GoogleDeviceURL,
+				JWKSURL:     GoogleJWKSURL,
+				UserInfoURL: GoogleUserInfoURL,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		clientID, _ := NewClientID("ClientID", "ClientSecret", "openid", "")
+		clientID.SetEndpoint(test.input)
+
+		if *clientID.Endpoint != *test.want {
+			t.Errorf(`TestClientIDEndpoint(%v) = %v, expected result to be %v`, test.input, clientID.Endpoint, test.want)
+		}
+		if clientID.RefreshToken.GetTokenURL() != test.want.TokenURL {
+			t.Errorf(`TestClientIDEndpoint(%v) = %q, expected token URL to be %q`, test.input, clientID.RefreshToken.GetTokenURL(), test.want.TokenURL)
+		}
+
+		clientID.RefreshToken.SetAuthURL(clientID)
+		if !strings.HasPrefix(clientID.RefreshToken.GetAuthURL(), test.want.AuthURL+"?") {
+			t.Errorf(`TestClientIDEndpoint(%v) = %q, expected auth URL to start with %q`, test.input, clientID.RefreshToken.GetAuthURL(), test.want.AuthURL)
+		}
+	}
+}
+
+func TestClientIDSetToken(t *testing.T) {
+	tests := []struct {
+		refreshToken string
+		body         string
+		want         string
+		rotated      bool
+		saved        string
+		ok           bool
+	}{
+		{
+			refreshToken: "SomeRefreshToken",
+			body:         `{"access_token":"SomeAccessToken","expires_in":3599}`,
+			want:         "SomeRefreshToken",
+			rotated:      false,
+			saved:        "",
+			ok:           true,
+		}, {
+			refreshToken: "SomeRefreshToken",
+			body:         `{"access_token":"SomeAccessToken","expires_in":3599,"refresh_token":"SomeRefreshToken"}`,
+			want:         "SomeRefreshToken",
+			rotated:      false,
+			saved:        "",
+			ok:           true,
+		}, {
+			refreshToken: "SomeRefreshToken",
+			body:         `{"access_token":"SomeAccessToken","expires_in":3599,"refresh_token":"NewRefreshToken"}`,
+			want:         "NewRefreshToken",
+			rotated:      true,
+			saved:        "NewRefreshToken",
+			ok:           true,
+		}, {
+			refreshToken: "",
+			body:         `{"access_token":"SomeAccessToken","expires_in":3599,"refresh_token":"NewRefreshToken"}`,
+			want:         "NewRefreshToken",
+			rotated:      false,
+			saved:        "NewRefreshToken",
+			ok:           true,
+		}, {
+			// the store's directory doesn't exist, so the new
+			// Refresh Token can't be persisted
+			refreshToken: "SomeRefreshToken",
+			body:         `{"access_token":"SomeAccessToken","expires_in":3599,"refresh_token":"NewRefreshToken"}`,
+			want:         "NewRefreshToken",
+			rotated:      true,
+			saved:        "",
+			ok:           false,
+		},
+	}
+
+	for _, test := range tests {
+		dir := t.TempDir()
+		if !test.ok {
+			dir = filepath.Join(dir, "missing")
+		}
+		store := NewFileStore(filepath.Join(dir, "refresh_token"))
+
+		clientID, _ := NewClientID("ClientID", "ClientSecret", "openid", test.refreshToken)
+		clientID.Store = store
+
+		err := clientID.SetToken([]byte(test.body))
+		if (err == nil) != test.ok {
+			t.Errorf(`TestClientIDSetToken(%q) = %v, expected error to be %v`, test.body, err, !test.ok)
+		}
+		// the error must not leak the new Refresh Token
+		if err != nil && strings.Contains(err.Error(), "NewRefreshToken") {
+			t.Errorf(`TestClientIDSetToken(%q) = %v, expected the error not to include the Refresh Token`, test.body, err)
+		}
+
+		if clientID.RefreshToken.GetToken() != test.want {
+			t.Errorf(`TestClientIDSetToken(%q) = %q, expected Refresh Token to be %q`, test.body, clientID.RefreshToken.GetToken(), test.want)
+		}
+		if clientID.RefreshToken.IsRotated() != test.rotated {
+			t.Errorf(`TestClientIDSetToken(%q) = %v, expected rotation to be %v`, test.body, clientID.RefreshToken.IsRotated(), test.rotated)
+		}
+		if saved, _ := store.Load(); saved != test.saved {
+			t.Errorf(`TestClientIDSetToken(%q) = %q, expected stored Refresh Token to be %q`, test.body, saved, test.saved)
+		}
+
+		// a later response without a new Refresh Token clears the rotation
+		clientID.SetToken([]byte(`{"access_token":"OtherAccessToken","expires_in":3599}`))
+		if clientID.RefreshToken.IsRotated() {
+			t.Errorf(`TestClientIDSetToken(%q) = %v, expected rotation to be reset`, test.body, clientID.RefreshToken.IsRotated())
+		}
+	}
+}
+
+func TestClientIDAuthParams(t *testing.T) {
+	tests := []struct {
+		params *AuthParams
+		want   string
+	}{
+		{
+			params: &AuthParams{
+				AccessType:           AccessTypeOnline,
+				Prompt:               "select_account",
+				LoginHint:            "user@example.com",
+				HostedDomain:         "example.com",
+				IncludeGrantedScopes: true,
+			},
+			want: "https://accounts.google.com/o/oauth2/auth?access_type=online&client_id=ClientID&hd=example.com&include_granted_scopes=true&login_hint=user%40example.com&prompt=select_account&redirect_uri=urn%3Aietf%3Awg%3Aoauth%3A2.0%3Aoob&response_type=code&scope=openid",
+		}, {
+			params: &AuthParams{},
+			want:   "https://accounts.google.com/o/oauth2/auth?client_id=ClientID&redirect_uri=urn%3Aietf%3Awg%3Aoauth%3A2.0%3Aoob&response_type=code&scope=openid",
+		},
+	}
+
+	for _, test := range tests {
+		clientID, _ := NewClientID("ClientID", "ClientSecret", "openid", "")
+		clientID.AuthParams = test.params
+
+		clientID.RefreshToken.SetAuthURL(clientID)
+		if clientID.RefreshToken.GetAuthURL() != test.want {
+			t.Errorf(`TestClientIDAuthParams(%v) = %q, expected result to be %q`, test.params, clientID.RefreshToken.GetAuthURL(), test.want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/url"
+)
+
+// ClientSecretFile struct represents the JSON file downloaded from
+// the Cloud Console for an OAuth client, which holds either an
+// installed (desktop) or web application client
+type ClientSecretFile struct {
+	Type      string        `json:"type,omitempty"`
+	Installed *ClientSecret `json:"installed,omitempty"`
+	Web       *ClientSecret `json:"web,omitempty"`
+}
+
+// ClientSecret struct represents the OAuth client's details in a
+// client_secret.json file
+type ClientSecret struct {
+	ClientID     string   `json:"client_id,omitempty"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+	ProjectID    string   `json:"project_id,omitempty"`
+	AuthURI      string   `json:"auth_uri,omitempty"`
+	TokenURI     string   `json:"token_uri,omitempty"`
+	RedirectURIs []string `json:"redirect_uris,omitempty"`
+}
+
+// ReadClientSecret function will read and validate the input
+// client_secret.json file, returning its OAuth client's details
+func ReadClientSecret(file string) (*ClientSecretFile, error) {
+	f, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := &ClientSecretFile{}
+	if err := json.Unmarshal(f, secret); err != nil {
+		return nil, errors.New(`Unable to parse ` + file + ` as a client_secret.json file: ` + err.Error())
+	}
+
+	if secret.Type != "" {
+		return nil, errors.New(file + ` is a ` + secret.Type + ` credential, not an OAuth client - expected a client_secret.json file from the Cloud Console`)
+	}
+
+	if secret.Get() == nil {
+		return nil, errors.New(file + ` is not an OAuth client - expected an "installed" or "web" client_secret.json file from the Cloud Console`)
+	}
+
+	return secret, nil
+}
+
+// Get method returns the ClientSecretFile's OAuth client details,
+// regardless of its application type
+func (f *ClientSecretFile) Get() *ClientSecret {
+	if f.Installed != nil {
+		return f.Installed
+	}
+	return f.Web
+}
+
+// IsWeb method checks whether the ClientSecretFile holds a web
+// application client, returning a boolean
+func (f *ClientSecretFile) IsWeb() bool {
+	return f.Installed == nil && f.Web != nil
+}
+
+// NewClientIDFromFile function will generate a Client ID based on
+// the input client_secret.json file, setting its authorization and
+// token endpoints from it. Web application clients also get their
+// first registered redirect URI set, as their redirect URIs must
+// match the registered ones, and installed application clients their
+// loopback redirect URI. authorized_user credentials files (like
+// gcloud's application_default_credentials.json) are also accepted
+func NewClientIDFromFile(file, scopes, refreshToken string) (*ClientID, error) {
+	if credType, err := CredentialType(file); err == nil && credType == CredentialTypeAuthorizedUser {
+		return NewClientIDFromAuthorizedUser(file, scopes, refreshToken)
+	}
+
+	secretFile, err := ReadClientSecret(file)
+	if err != nil {
+		return nil, err
+	}
+	secret := secretFile.Get()
+
+	client, err := NewClientID(secret.ClientID, secret.ClientSecret, scopes, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	client.SetEndpoint(&Endpoint{
+		AuthURL:  secret.AuthURI,
+		TokenURL: secret.TokenURI,
+	})
+
+	if secretFile.IsWeb() && len(secret.RedirectURIs) > 0 {
+		client.SetRedirectURI(secret.RedirectURIs[0])
+	} else if uri := loopbackRedirectURI(secret.RedirectURIs); uri != "" {
+		client.SetRedirectURI(uri)
+	}
+
+	return client, nil
+}
+
+// loopbackRedirectURI function returns the first loopback redirect
+// URI (like http://localhost) in the input list, or an empty string.
+// Installed application clients use it instead of the out-of-band
+// redirect, which Google no longer supports: the user pastes the
+// full redirect URL from the browser's address bar
+func loopbackRedirectURI(uris []string) string {
+	for _, uri := range uris {
+		u, err := url.Parse(uri)
+		if err != nil || u.Scheme != "http" {
+			continue
+		}
+
+		switch u.Hostname() {
+		case "localhost", loopbackHost, "::1":
+			return uri
+		}
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package oauth
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewClientIDFromFile(t *testing.T) {
+	tests := []struct {
+		content  string
+		id       string
+		redirect string
+		ok       bool
+	}{
+		{
+			content:  `{"installed":{"client_id":"ClientID","project_id":"project","auth_uri":"https://accounts.google.com/o/oauth2/auth","token_uri":"https://oauth2.googleapis.com/token","client_secret":"ClientSecret","redirect_uris":["http://localhost"]}}`,
+			id:       "ClientID",
+			redirect: "http://localhost",
+			ok:       true,
+		}, {
+			content:  `{"installed":{"client_id":"ClientID","project_id":"project","auth_uri":"https://accounts.google.com/o/oauth2/auth","token_uri":"https://oauth2.googleapis.com/token","client_secret":"ClientSecret","redirect_uris":["urn:ietf:wg:oauth:2.0:oob","http://127.0.0.1:8080"]}}`,
+			id:       "ClientID",
+			redirect: "http://127.0.0.1:8080",
+			ok:       true,
+		}, {
+			content:  `{"installed":{"client_id":"ClientID","project_id":"project","auth_uri":"https://accounts.google.com/o/oauth2/auth","token_uri":"https://oauth2.googleapis.com/token","client_secret":"ClientSecret"}}`,
+			id:       "ClientID",
+			redirect: oobRedirectURI,
+			ok:       true,
+		}, {
+			content:  `{"web":{"client_id":"WebClientID","project_id":"project","auth_uri":"https://accounts.google.com/o/oauth2/auth","token_uri":"https://oauth2.googleapis.com/token","client_secret":"ClientSecret","redirect_uris":["https://app.example.com/callback"]}}`,
+			id:       "WebClientID",
+			redirect: "https://app.example.com/callback",
+			ok:       true,
+		}, {
+			content: `{"type":"service_account","project_id":"project","private_key_id":"abc","client_email":"sa@project.iam.gserviceaccount.com"}`,
+			ok:      false,
+		}, {
+			content: `{"installed":{"client_id":"ClientID"}}`,
+			ok:      false,
+		}, {
+			content: `{}`,
+			ok:      false,
+		}, {
+			content: `not json`,
+			ok:      false,
+		},
+	}
+
+	dir := t.TempDir()
+
+	for _, test := range tests {
+		file := filepath.Join(dir, "client_secret.json")
+		if err := ioutil.WriteFile(file, []byte(test.content), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		clientID, err := NewClientIDFromFile(file, "https://www.googleapis.com/auth/userinfo.email", "")
+		if (err == nil) != test.ok {
+			t.Errorf(`TestNewClientIDFromFile(%q) = %v, expected error to be %v`, test.content, err, !test.ok)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		if clientID.GetID() != test.id {
+			t.Errorf(`TestNewClientIDFromFile(%q) = %q, expected Client ID to be %q`, test.content, clientID.GetID(), test.id)
+		}
+		if clientID.GetRedirectURI() != test.redirect {
+			t.Errorf(`TestNewClientIDFromFile(%q) = %q, expected redirect URI to be %q`, test.content, clientID.GetRedirectURI(), test.redirect)
+		}
+		if clientID.RefreshToken.GetTokenURL() != "https://oauth2.googleapis.com/token" {
+			t.Errorf(`TestNewClientIDFromFile(%q) = %q, expected token URL from file`, test.content, clientID.RefreshToken.GetTokenURL())
+		}
+	}
+}
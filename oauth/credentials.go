@@ -0,0 +1,41 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+)
+
+const (
+	// CredentialTypeServiceAccount is the type of service account
+	// JSON keyfiles
+	CredentialTypeServiceAccount string = `service_account`
+
+	// CredentialTypeExternalAccount is the type of Workload Identity
+	// Federation credential configuration files
+	CredentialTypeExternalAccount string = `external_account`
+
+	// CredentialTypeAuthorizedUser is the type of gcloud's user
+	// credentials files, holding a Client ID and a Refresh Token
+	CredentialTypeAuthorizedUser string = `authorized_user`
+)
+
+type credentialFile struct {
+	Type string `json:"type"`
+}
+
+// CredentialType function will read the input JSON credentials file,
+// returning its type (like service_account or external_account)
+func CredentialType(file string) (string, error) {
+	f, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+
+	cred := &credentialFile{}
+	if err := json.Unmarshal(f, cred); err != nil {
+		return "", errors.New(`Invalid credentials file ` + file + `: ` + err.Error())
+	}
+
+	return cred.Type, nil
+}
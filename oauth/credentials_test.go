@@ -0,0 +1,55 @@
+package oauth
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestCredentialType(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		content string
+		want    string
+		ok      bool
+	}{
+		{
+			content: `{"type":"service_account","client_email":"account@project.iam.gserviceaccount.com"}`,
+			want:    CredentialTypeServiceAccount,
+			ok:      true,
+		}, {
+			content: `{"type":"external_account","audience":"//iam.googleapis.com/pool"}`,
+			want:    CredentialTypeExternalAccount,
+			ok:      true,
+		}, {
+			content: `{"installed":{"client_id":"id"}}`,
+			want:    "",
+			ok:      true,
+		}, {
+			content: `not json`,
+			ok:      false,
+		},
+	}
+
+	for idx, test := range tests {
+		file := filepath.Join(dir, "credentials.json")
+		if err := ioutil.WriteFile(file, []byte(test.content), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := CredentialType(file)
+		if (err == nil) != test.ok {
+			t.Errorf(`TestCredentialType(%v) = %v, expected error to be %v`, idx, err, !test.ok)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf(`TestCredentialType(%v) = %q, expected result to be %q`, idx, got, test.want)
+		}
+	}
+
+	if _, err := CredentialType(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf(`TestCredentialType(missing) = nil, expected an error`)
+	}
+}
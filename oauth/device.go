@@ -0,0 +1,136 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	deviceGrantType string = `urn:ietf:params:oauth:grant-type:device_code`
+
+	deviceInterval  time.Duration = 5 * time.Second
+	deviceSlowDown  time.Duration = 5 * time.Second
+	deviceExpiresIn time.Duration = 30 * time.Minute
+)
+
+// sleep and now are replaced in tests, to poll without waiting
+var (
+	sleep = time.Sleep
+	now   = time.Now
+)
+
+// ErrDeviceExpired is returned when the device code expires before
+// the user completes the authorization
+var ErrDeviceExpired = errors.New(`The device code expired before the authorization was completed`)
+
+// DeviceCode struct represents a JSON response from a device
+// authorization endpoint (RFC 8628)
+type DeviceCode struct {
+	DeviceCode              string `json:"device_code,omitempty"`
+	UserCode                string `json:"user_code,omitempty"`
+	VerificationURI         string `json:"verification_uri,omitempty"`
+	VerificationURL         string `json:"verification_url,omitempty"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int    `json:"expires_in,omitempty"`
+	Interval                int    `json:"interval,omitempty"`
+}
+
+// GetVerificationURI method returns the URL the user should visit
+// to enter the user code. Google returns it as verification_url
+// instead of RFC 8628's verification_uri, so both are checked
+func (d *DeviceCode) GetVerificationURI() string {
+	if d.VerificationURI != "" {
+		return d.VerificationURI
+	}
+	return d.VerificationURL
+}
+
+// Device method will initiate the Device Authorization Grant (RFC
+// 8628) for the ClientID, for environments where no browser is
+// available. The user is prompted to visit a verification URL (on
+// any device) and enter a code, while the token endpoint is polled
+// until the authorization is completed
+func (c *ClientID) Device() error {
+	body, err := c.PostForm(c.RefreshToken.GetDeviceURL(), url.Values{
+		"scope": {c.GetScopes()},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := ParseTokenError(body); err != nil {
+		return err
+	}
+
+	device := &DeviceCode{}
+	if err := json.Unmarshal(body, device); err != nil {
+		return err
+	}
+
+	fmt.Println(`Please visit the following URL on any device and enter the code below: 
+===
+` + device.GetVerificationURI() + `
+===
+User Code:	` + device.UserCode)
+
+	if device.VerificationURIComplete != "" {
+		fmt.Println(`
+Alternatively, visit the following URL which already includes the code:
+` + device.VerificationURIComplete)
+	}
+
+	return c.PollDevice(device)
+}
+
+// PollDevice method will poll the token endpoint with the input
+// DeviceCode, honouring its interval and slow_down responses, until
+// the user completes (or denies) the authorization, or the device
+// code expires
+func (c *ClientID) PollDevice(device *DeviceCode) error {
+	interval := deviceInterval
+	if device.Interval > 0 {
+		interval = time.Duration(device.Interval) * time.Second
+	}
+
+	expiresIn := deviceExpiresIn
+	if device.ExpiresIn > 0 {
+		expiresIn = time.Duration(device.ExpiresIn) * time.Second
+	}
+	deadline := now().Add(expiresIn)
+
+	for {
+		sleep(interval)
+
+		if now().After(deadline) {
+			return ErrDeviceExpired
+		}
+
+		body, err := c.PostForm(c.RefreshToken.GetTokenURL(), url.Values{
+			"device_code": {device.DeviceCode},
+			"grant_type":  {deviceGrantType},
+		})
+		if err != nil {
+			return err
+		}
+
+		chk := &TokenError{}
+		json.Unmarshal(body, chk)
+
+		switch chk.Error {
+		case "":
+			return c.SetToken(body)
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += deviceSlowDown
+			continue
+		case "expired_token":
+			return ErrDeviceExpired
+		default:
+			return ParseTokenError(body)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock function will replace the sleep and now functions with a
+// clock which only advances when sleeping, recording each sleep
+func fakeClock(t *testing.T) *[]time.Duration {
+	slept := &[]time.Duration{}
+	clock := time.Now()
+
+	sleep = func(d time.Duration) {
+		*slept = append(*slept, d)
+		clock = clock.Add(d)
+	}
+	now = func() time.Time {
+		return clock
+	}
+
+	t.Cleanup(func() {
+		sleep = time.Sleep
+		now = time.Now
+	})
+	return slept
+}
+
+func TestClientIDPollDevice(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []string
+		expiresIn int
+		want      string
+		err       error
+		ok        bool
+		slept     []time.Duration
+	}{
+		{
+			name:      "authorized",
+			responses: []string{`{"access_token":"SomeAccessToken","expires_in":3599}`},
+			want:      "SomeAccessToken",
+			ok:        true,
+		}, {
+			name: "authorization pending",
+			responses: []string{
+				`{"error":"authorization_pending"}`,
+				`{"error":"authorization_pending"}`,
+				`{"access_token":"SomeAccessToken","expires_in":3599}`,
+			},
+			want:  "SomeAccessToken",
+			ok:    true,
+			slept: []time.Duration{time.Second, time.Second, time.Second},
+		}, {
+			// slow_down increases the 1 second interval by 5 seconds
+			name: "slow down",
+			responses: []string{
+				`{"error":"slow_down"}`,
+				`{"access_token":"SomeAccessToken","expires_in":3599}`,
+			},
+			want:  "SomeAccessToken",
+			ok:    true,
+			slept: []time.Duration{time.Second, time.Second + deviceSlowDown},
+		}, {
+			name:      "expired token",
+			responses: []string{`{"error":"expired_token"}`},
+			err:       ErrDeviceExpired,
+			ok:        false,
+		}, {
+			name: "deadline",
+			responses: []string{
+				`{"error":"authorization_pending"}`,
+				`{"error":"authorization_pending"}`,
+				`{"error":"authorization_pending"}`,
+			},
+			expiresIn: 1,
+			err:       ErrDeviceExpired,
+			ok:        false,
+		}, {
+			name:      "access denied",
+			responses: []string{`{"error":"access_denied","error_description":"The user denied the request"}`},
+			ok:        false,
+		},
+	}
+
+	for _, test := range tests {
+		slept := fakeClock(t)
+		requests := 0
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.ParseForm()
+			if r.PostForm.Get("grant_type") != deviceGrantType || r.PostForm.Get("device_code") != "SomeDeviceCode" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"invalid_request"}`))
+				return
+			}
+
+			requests++
+			if requests > len(test.responses) {
+				w.Write([]byte(`{"error":"authorization_pending"}`))
+				return
+			}
+			w.Write([]byte(test.responses[requests-1]))
+		}))
+
+		clientID, _ := NewClientID("ClientID", "ClientSecret", "openid", "")
+		clientID.RefreshToken.SetTokenURL(server.URL)
+
+		err := clientID.PollDevice(&DeviceCode{
+			DeviceCode: "SomeDeviceCode",
+			ExpiresIn:  test.expiresIn,
+			Interval:   1,
+		})
+		server.Close()
+
+		if (err == nil) != test.ok {
+			t.Errorf(`TestClientIDPollDevice(%s) = %v, expected error to be %v`, test.name, err, !test.ok)
+			continue
+		}
+		if test.err != nil && err != test.err {
+			t.Errorf(`TestClientIDPollDevice(%s) = %v, expected error to be %v`, test.name, err, test.err)
+		}
+		if !test.ok && test.err == nil && !strings.Contains(err.Error(), "access_denied") {
+			t.Errorf(`TestClientIDPollDevice(%s) = %v, expected error to be access_denied`, test.name, err)
+		}
+		if clientID.AccessToken.Token != test.want {
+			t.Errorf(`TestClientIDPollDevice(%s) = %q, expected result to be %q`, test.name, clientID.AccessToken.Token, test.want)
+		}
+
+		if test.expiresIn == 0 && requests != len(test.responses) {
+			t.Errorf(`TestClientIDPollDevice(%s) = %d requests, expected %d`, test.name, requests, len(test.responses))
+		}
+		if test.slept != nil && !reflect.DeepEqual(*slept, test.slept) {
+			t.Errorf(`TestClientIDPollDevice(%s) = %v, expected the polling intervals to be %v`, test.name, *slept, test.slept)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package oauth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	oidcDiscoveryPath  string = `/.well-known/openid-configuration`
+	oauthDiscoveryPath string = `/.well-known/oauth-authorization-server`
+
+	// DiscoveryTTL is the default time for which fetched provider
+	// metadata is cached on disk
+	DiscoveryTTL time.Duration = 24 * time.Hour
+)
+
+// ProviderMetadata struct represents an authorization server's
+// metadata, as served by OpenID Connect Discovery or RFC 8414
+type ProviderMetadata struct {
+	Issuer                        string   `json:"issuer,omitempty"`
+	AuthorizationEndpoint         string   `json:"authorization_endpoint,omitempty"`
+	TokenEndpoint                 string   `json:"token_endpoint,omitempty"`
+	RevocationEndpoint            string   `json:"revocation_endpoint,omitempty"`
+	DeviceAuthorizationEndpoint   string   `json:"device_authorization_endpoint,omitempty"`
+	IntrospectionEndpoint         string   `json:"introspection_endpoint,omitempty"`
+	UserInfoEndpoint              string   `json:"userinfo_endpoint,omitempty"`
+	JWKSURI                       string   `json:"jwks_uri,omitempty"`
+	ScopesSupported               []string `json:"scopes_supported,omitempty"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported,omitempty"`
+}
+
+// discoveryCache struct represents the provider metadata as stored
+// on disk, along with the time it was fetched
+type discoveryCache struct {
+	Fetched  int64             `json:"fetched"`
+	Metadata *ProviderMetadata `json:"metadata"`
+}
+
+// Discover function will fetch the input issuer's metadata from its
+// OpenID Connect discovery document, falling back to its RFC 8414
+// authorization server metadata
+func Discover(issuer string) (*ProviderMetadata, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+
+	u, err := url.Parse(issuer)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, errors.New(`Invalid issuer URL: ` + issuer)
+	}
+
+	// RFC 8414 inserts the well-known path between the host and the
+	// issuer's path, unlike OpenID Connect which appends it
+	oauthURL := u.Scheme + `://` + u.Host + oauthDiscoveryPath + u.Path
+
+	var errs []string
+	for _, wellKnown := range []string{issuer + oidcDiscoveryPath, oauthURL} {
+		metadata, err := fetchMetadata(wellKnown)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		if strings.TrimSuffix(metadata.Issuer, "/") != issuer {
+			return nil, errors.New(`Issuer mismatch in provider metadata: expected ` + issuer + `, got ` + metadata.Issuer)
+		}
+		return metadata, nil
+	}
+
+	return nil, errors.New(`Unable to discover provider metadata for ` + issuer + `:
+	` + strings.Join(errs, "\n\t"))
+}
+
+// DiscoverCached function will return the input issuer's metadata
+// from the on-disk cache in the input directory, if it was fetched
+// within the input TTL. Otherwise, the metadata is fetched with
+// Discover and stored in the cache. If the issuer can't be reached,
+// a stale cached copy is returned instead, when available. An empty
+// directory will use the default cache directory
+func DiscoverCached(issuer, dir string, ttl time.Duration) (*ProviderMetadata, error) {
+	if dir == "" {
+		var err error
+		if dir, err = DiscoveryCacheDir(); err != nil {
+			return Discover(issuer)
+		}
+	}
+
+	hash := sha256.Sum256([]byte(strings.TrimSuffix(issuer, "/")))
+	file := filepath.Join(dir, hex.EncodeToString(hash[:])+`.json`)
+
+	cached := &discoveryCache{}
+	if f, err := ioutil.ReadFile(file); err == nil {
+		if err := json.Unmarshal(f, cached); err != nil {
+			cached.Metadata = nil
+		}
+	}
+
+	if cached.Metadata != nil && time.Since(time.Unix(cached.Fetched, 0)) < ttl {
+		return cached.Metadata, nil
+	}
+
+	metadata, err := Discover(issuer)
+	if err != nil {
+		if cached.Metadata != nil {
+			return cached.Metadata, nil
+		}
+		return nil, err
+	}
+
+	// a cache that can't be written isn't fatal, but it is reported,
+	// as the metadata is then fetched on every run
+	if err := writeDiscoveryCache(dir, file, metadata); err != nil {
+		fmt.Fprintln(os.Stderr, `Unable to cache the issuer metadata: `+err.Error())
+	}
+
+	return metadata, nil
+}
+
+func writeDiscoveryCache(dir, file string, metadata *ProviderMetadata) error {
+	buf, err := json.Marshal(&discoveryCache{
+		Fetched:  time.Now().Unix(),
+		Metadata: metadata,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, buf, 0600)
+}
+
+// DiscoveryCacheDir function returns the default directory for
+// cached provider metadata, within the user's cache directory
+func DiscoveryCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, `goauth`, `discovery`), nil
+}
+
+func fetchMetadata(wellKnown string) (*ProviderMetadata, error) {
+	resp, err := http.Get(wellKnown)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(wellKnown + `: HTTP ` + strconv.Itoa(resp.StatusCode))
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &ProviderMetadata{}
+	if err := json.Unmarshal(body, metadata); err != nil {
+		return nil, errors.New(wellKnown + `: ` + err.Error())
+	}
+
+	return metadata, nil
+}
+
+// Endpoint method returns an Endpoint with the URLs from the
+// ProviderMetadata
+func (m *ProviderMetadata) Endpoint() *Endpoint {
+	return &Endpoint{
+		Issuer:      m.Issuer,
+		AuthURL:     m.AuthorizationEndpoint,
+		TokenURL:    m.TokenEndpoint,
+		RevokeURL:   m.RevocationEndpoint,
+		DeviceURL:   m.DeviceAuthorizationEndpoint,
+		JWKSURL:     m.JWKSURI,
+		UserInfoURL: m.UserInfoEndpoint,
+	}
+}
+
+// Discover method will replace the ClientID's endpoints with the
+// ones found in the input issuer's metadata, which is cached on disk
+// in the input directory (or the default one, if empty) for the input
+// TTL
+func (c *ClientID) Discover(issuer, dir string, ttl time.Duration) error {
+	metadata, err := DiscoverCached(issuer, dir, ttl)
+	if err != nil {
+		return err
+	}
+
+	c.Endpoint = metadata.Endpoint()
+	c.SetEndpoint(nil)
+	return nil
+}
@@ -0,0 +1,177 @@
+package oauth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDiscover(t *testing.T) {
+	var issuer string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oidc"+oidcDiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&ProviderMetadata{
+			Issuer:                issuer + "/oidc",
+			AuthorizationEndpoint: issuer + "/oidc/auth",
+			TokenEndpoint:         issuer + "/oidc/token",
+			JWKSURI:               issuer + "/oidc/certs",
+		})
+	})
+	mux.HandleFunc(oauthDiscoveryPath+"/oauth", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&ProviderMetadata{
+			Issuer:                issuer + "/oauth",
+			AuthorizationEndpoint: issuer + "/oauth/auth",
+			TokenEndpoint:         issuer + "/oauth/token",
+		})
+	})
+	mux.HandleFunc("/other"+oidcDiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&ProviderMetadata{
+			Issuer: "https://attacker.example.com",
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	issuer = server.URL
+
+	tests := []struct {
+		issuer string
+		want   string
+		ok     bool
+	}{
+		{
+			issuer: issuer + "/oidc",
+			want:   issuer + "/oidc/token",
+			ok:     true,
+		}, {
+			issuer: issuer + "/oauth/",
+			want:   issuer + "/oauth/token",
+			ok:     true,
+		}, {
+			issuer: issuer + "/other",
+			ok:     false,
+		}, {
+			issuer: issuer + "/missing",
+			ok:     false,
+		},
+	}
+
+	for _, test := range tests {
+		metadata, err := Discover(test.issuer)
+		if (err == nil) != test.ok {
+			t.Errorf(`TestDiscover(%q) = %v, expected error to be %v`, test.issuer, err, !test.ok)
+			continue
+		}
+		if err == nil && metadata.Endpoint().TokenURL != test.want {
+			t.Errorf(`TestDiscover(%q) = %q, expected token URL to be %q`, test.issuer, metadata.Endpoint().TokenURL, test.want)
+		}
+	}
+}
+
+func TestDiscoverCached(t *testing.T) {
+	var issuer string
+	var hits int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		json.NewEncoder(w).Encode(&ProviderMetadata{
+			Issuer:        issuer,
+			TokenEndpoint: issuer + "/token",
+		})
+	}))
+	issuer = server.URL
+	dir := t.TempDir()
+
+	for i := 0; i < 2; i++ {
+		if _, err := DiscoverCached(issuer, dir, time.Hour); err != nil {
+			t.Fatalf(`TestDiscoverCached(%q) = %v, expected no error`, issuer, err)
+		}
+	}
+	if hits != 1 {
+		t.Errorf(`TestDiscoverCached(%q) fetched metadata %d times, expected 1`, issuer, hits)
+	}
+
+	// expired cache, with the issuer unreachable
+	server.Close()
+	metadata, err := DiscoverCached(issuer, dir, 0)
+	if err != nil {
+		t.Fatalf(`TestDiscoverCached(%q) = %v, expected stale cache to be used`, issuer, err)
+	}
+	if metadata.TokenEndpoint != issuer+"/token" {
+		t.Errorf(`TestDiscoverCached(%q) = %q, expected token URL to be %q`, issuer, metadata.TokenEndpoint, issuer+"/token")
+	}
+}
+
+func TestDiscoverCachedStale(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	issuer := server.URL
+	server.Close()
+
+	dir := t.TempDir()
+	hash := sha256.Sum256([]byte(issuer))
+
+	tests := []struct {
+		cache string
+		want  string
+		ok    bool
+	}{
+		{
+			// fetched a week ago, with the issuer unreachable
+			cache: `{"fetched":` + strconv.FormatInt(time.Now().Add(-7*24*time.Hour).Unix(), 10) + `,"metadata":{"issuer":"` + issuer + `","token_endpoint":"` + issuer + `/token"}}`,
+			want:  issuer + "/token",
+			ok:    true,
+		}, {
+			cache: `not json`,
+			ok:    false,
+		}, {
+			ok: false,
+		},
+	}
+
+	for _, test := range tests {
+		file := filepath.Join(dir, hex.EncodeToString(hash[:])+".json")
+		os.Remove(file)
+		if test.cache != "" {
+			if err := ioutil.WriteFile(file, []byte(test.cache), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		metadata, err := DiscoverCached(issuer, dir, time.Hour)
+		if (err == nil) != test.ok {
+			t.Errorf(`TestDiscoverCachedStale(%q) = %v, expected error to be %v`, test.cache, err, !test.ok)
+			continue
+		}
+		if err == nil && metadata.TokenEndpoint != test.want {
+			t.Errorf(`TestDiscoverCachedStale(%q) = %q, expected token URL to be %q`, test.cache, metadata.TokenEndpoint, test.want)
+		}
+	}
+}
+
+func TestWriteDiscoveryCache(t *testing.T) {
+	metadata := &ProviderMetadata{Issuer: "https://issuer.example.com"}
+
+	dir := filepath.Join(t.TempDir(), "discovery")
+	if err := writeDiscoveryCache(dir, filepath.Join(dir, "cache.json"), metadata); err != nil {
+		t.Errorf(`TestWriteDiscoveryCache(%q) = %v, expected no error`, dir, err)
+	}
+
+	// the cache directory can't be created under a regular file
+	parent := filepath.Join(t.TempDir(), "file")
+	if err := ioutil.WriteFile(parent, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	dir = filepath.Join(parent, "discovery")
+	if err := writeDiscoveryCache(dir, filepath.Join(dir, "cache.json"), metadata); err == nil {
+		t.Errorf(`TestWriteDiscoveryCache(%q) = nil, expected an error`, dir)
+	}
+}
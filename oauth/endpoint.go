@@ -0,0 +1,78 @@
+package oauth
+
+const (
+	// GoogleAuthURL is Google's authorization endpoint
+	GoogleAuthURL string = `https://accounts.google.com/o/oauth2/auth`
+
+	// GoogleTokenURL is Google's token endpoint
+	GoogleTokenURL string = `https://accounts.google.com/o/oauth2/token`
+
+	// GoogleRevokeURL is Google's token revocation endpoint
+	GoogleRevokeURL string = `https://oauth2.googleapis.com/revoke`
+
+	// GoogleDeviceURL is Google's device authorization endpoint
+	GoogleDeviceURL string = `https://oauth2.googleapis.com/device/code`
+
+	// GoogleJWKSURL is Google's JSON Web Key Set endpoint
+	GoogleJWKSURL string = `https://www.googleapis.com/oauth2/v3/certs`
+
+	// GoogleUserInfoURL is Google's OpenID Connect userinfo endpoint
+	GoogleUserInfoURL string = `https://openidconnect.googleapis.com/v1/userinfo`
+)
+
+// Endpoint struct represents the set of URLs exposed by an
+// authorization server
+type Endpoint struct {
+	Issuer      string
+	AuthURL     string
+	TokenURL    string
+	RevokeURL   string
+	DeviceURL   string
+	JWKSURL     string
+	UserInfoURL string
+}
+
+// GoogleEndpoint function returns the Endpoint for Google's
+// authorization server, used by default
+func GoogleEndpoint() *Endpoint {
+	return &Endpoint{
+		Issuer:      GoogleIssuer,
+		AuthURL:     GoogleAuthURL,
+		TokenURL:    GoogleTokenURL,
+		RevokeURL:   GoogleRevokeURL,
+		DeviceURL:   GoogleDeviceURL,
+		JWKSURL:     GoogleJWKSURL,
+		UserInfoURL: GoogleUserInfoURL,
+	}
+}
+
+// Merge method will override the Endpoint's URLs with the ones
+// set in the input Endpoint, keeping the current value for any
+// URL which is not set
+func (e *Endpoint) Merge(input *Endpoint) *Endpoint {
+	if input == nil {
+		return e
+	}
+	if input.Issuer != "" {
+		e.Issuer = input.Issuer
+	}
+	if input.AuthURL != "" {
+		e.AuthURL = input.AuthURL
+	}
+	if input.TokenURL != "" {
+		e.TokenURL = input.TokenURL
+	}
+	if input.RevokeURL != "" {
+		e.RevokeURL = input.RevokeURL
+	}
+	if input.DeviceURL != "" {
+		e.DeviceURL = input.DeviceURL
+	}
+	if input.JWKSURL != "" {
+		e.JWKSURL = input.JWKSURL
+	}
+	if input.UserInfoURL != "" {
+		e.UserInfoURL = input.UserInfoURL
+	}
+	return e
+}
@@ -0,0 +1,139 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+)
+
+const (
+	// GoogleSTSURL is Google's Security Token Service endpoint
+	GoogleSTSURL string = `https://sts.googleapis.com/v1/token`
+
+	tokenExchangeGrantType string = `urn:ietf:params:oauth:grant-type:token-exchange`
+	tokenTypeURIPrefix     string = `urn:ietf:params:oauth:token-type:`
+)
+
+var tokenTypeURIs = map[string]string{
+	"access_token":  tokenTypeURIPrefix + "access_token",
+	"refresh_token": tokenTypeURIPrefix + "refresh_token",
+	"id_token":      tokenTypeURIPrefix + "id_token",
+	"jwt":           tokenTypeURIPrefix + "jwt",
+	"saml1":         tokenTypeURIPrefix + "saml1",
+	"saml2":         tokenTypeURIPrefix + "saml2",
+}
+
+// TokenTypeURI function returns the RFC 8693 token type URI for the
+// input short name (access_token, refresh_token, id_token, jwt, saml1
+// or saml2). Any other input is returned as-is, as it is expected to
+// already be a URI
+func TokenTypeURI(input string) string {
+	if uri, ok := tokenTypeURIs[input]; ok {
+		return uri
+	}
+	return input
+}
+
+// TokenExchange struct represents an OAuth 2.0 Token Exchange (RFC
+// 8693) request, where a subject token (and optionally an actor
+// token) is exchanged for a new token at a Security Token Service
+type TokenExchange struct {
+	Endpoint           string
+	ClientID           string
+	Secret             string
+	SubjectToken       string
+	SubjectTokenType   string
+	ActorToken         string
+	ActorTokenType     string
+	RequestedTokenType string
+	Audience           string
+	Resource           string
+	Scopes             string
+	AccessToken        *AccessToken
+}
+
+// NewTokenExchange function will create a TokenExchange object for
+// the input STS endpoint and subject token. The subject token type
+// can be either a short name (like id_token) or a token type URI
+func NewTokenExchange(endpoint, subjectToken, subjectTokenType string) (*TokenExchange, error) {
+	if endpoint == "" {
+		return nil, errors.New(`STS endpoint not defined - mandatory field`)
+	}
+	if subjectToken == "" {
+		return nil, errors.New(`Subject token not defined - mandatory field`)
+	}
+	if subjectTokenType == "" {
+		return nil, errors.New(`Subject token type not defined - mandatory field`)
+	}
+
+	return &TokenExchange{
+		Endpoint:         endpoint,
+		SubjectToken:     subjectToken,
+		SubjectTokenType: TokenTypeURI(subjectTokenType),
+		AccessToken:      &AccessToken{},
+	}, nil
+}
+
+// SetActor method will define the actor token (and its type) for the
+// TokenExchange object, for delegation scenarios
+func (t *TokenExchange) SetActor(token, tokenType string) {
+	t.ActorToken = token
+	t.ActorTokenType = TokenTypeURI(tokenType)
+	return
+}
+
+// SetRequestedTokenType method will define the type of the token
+// to be issued for the TokenExchange object
+func (t *TokenExchange) SetRequestedTokenType(input string) {
+	t.RequestedTokenType = TokenTypeURI(input)
+	return
+}
+
+// SetClient method will define the client credentials for the
+// TokenExchange object, for STS endpoints requiring authentication
+func (t *TokenExchange) SetClient(id, secret string) {
+	t.ClientID = id
+	t.Secret = secret
+	return
+}
+
+// Exchange method will post the TokenExchange request to its STS
+// endpoint, storing the issued token in its AccessToken
+func (t *TokenExchange) Exchange() error {
+	form := url.Values{
+		"grant_type":         {tokenExchangeGrantType},
+		"subject_token":      {t.SubjectToken},
+		"subject_token_type": {t.SubjectTokenType},
+	}
+
+	if t.ActorToken != "" {
+		form.Set("actor_token", t.ActorToken)
+		form.Set("actor_token_type", t.ActorTokenType)
+	}
+	if t.RequestedTokenType != "" {
+		form.Set("requested_token_type", t.RequestedTokenType)
+	}
+	if t.Audience != "" {
+		form.Set("audience", t.Audience)
+	}
+	if t.Resource != "" {
+		form.Set("resource", t.Resource)
+	}
+	if t.Scopes != "" {
+		form.Set("scope", t.Scopes)
+	}
+
+	body, err := postForm(t.Endpoint, form, t.ClientID, t.Secret)
+	if err != nil {
+		return err
+	}
+
+	if err := ParseTokenError(body); err != nil {
+		return err
+	}
+
+	if t.AccessToken == nil {
+		t.AccessToken = &AccessToken{}
+	}
+	return json.Unmarshal(body, t.AccessToken)
+}
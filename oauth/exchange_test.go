@@ -0,0 +1,79 @@
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTokenExchange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+
+		if r.PostForm.Get("grant_type") != tokenExchangeGrantType ||
+			r.PostForm.Get("subject_token_type") != tokenTypeURIPrefix+"id_token" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_request","error_description":"unsupported token type"}`))
+			return
+		}
+		if r.PostForm.Get("subject_token") != "SubjectToken" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant","error_description":"invalid subject token"}`))
+			return
+		}
+
+		w.Write([]byte(`{"access_token":"ExchangedToken","issued_token_type":"` + r.PostForm.Get("requested_token_type") + `","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		token     string
+		tokenType string
+		want      string
+		ok        bool
+	}{
+		{
+			token:     "SubjectToken",
+			tokenType: "id_token",
+			want:      "ExchangedToken",
+			ok:        true,
+		}, {
+			token:     "SubjectToken",
+			tokenType: tokenTypeURIPrefix + "id_token",
+			want:      "ExchangedToken",
+			ok:        true,
+		}, {
+			token:     "OtherToken",
+			tokenType: "id_token",
+			ok:        false,
+		}, {
+			token:     "SubjectToken",
+			tokenType: "saml2",
+			ok:        false,
+		},
+	}
+
+	for _, test := range tests {
+		exchange, err := NewTokenExchange(server.URL, test.token, test.tokenType)
+		if err != nil {
+			t.Fatalf(`TestTokenExchange(%q, %q) = %v, expected no error`, test.token, test.tokenType, err)
+		}
+		exchange.SetRequestedTokenType("access_token")
+
+		err = exchange.Exchange()
+		if (err == nil) != test.ok {
+			t.Errorf(`TestTokenExchange(%q, %q) = %v, expected error to be %v`, test.token, test.tokenType, err, !test.ok)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		if exchange.AccessToken.Token != test.want {
+			t.Errorf(`TestTokenExchange(%q, %q) = %q, expected result to be %q`, test.token, test.tokenType, exchange.AccessToken.Token, test.want)
+		}
+		if exchange.AccessToken.IssuedTokenType != tokenTypeURIPrefix+"access_token" {
+			t.Errorf(`TestTokenExchange(%q, %q) = %q, expected issued token type to be set`, test.token, test.tokenType, exchange.AccessToken.IssuedTokenType)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	credentialFormatText string = `text`
+	credentialFormatJSON string = `json`
+)
+
+// ExternalAccount struct represents a Workload Identity Federation
+// credential configuration (external_account), where a token issued
+// by an external identity provider is exchanged for a Google Access
+// Token at the Security Token Service, and optionally used to
+// impersonate a service account
+type ExternalAccount struct {
+	Type                           string                          `json:"type"`
+	Audience                       string                          `json:"audience"`
+	SubjectTokenType               string                          `json:"subject_token_type"`
+	TokenURL                       string                          `json:"token_url"`
+	ServiceAccountImpersonationURL string                          `json:"service_account_impersonation_url,omitempty"`
+	ServiceAccountImpersonation    *ServiceAccountImpersonationCfg `json:"service_account_impersonation,omitempty"`
+	CredentialSource               *CredentialSource               `json:"credential_source"`
+	QuotaProjectID                 string                          `json:"quota_project_id,omitempty"`
+	AccessToken                    *AccessToken                    `json:"-"`
+}
+
+// ServiceAccountImpersonationCfg struct represents the service
+// account impersonation settings of an ExternalAccount
+type ServiceAccountImpersonationCfg struct {
+	TokenLifetimeSeconds int `json:"token_lifetime_seconds,omitempty"`
+}
+
+// CredentialSource struct represents the source of an
+// ExternalAccount's subject token: either a file or a URL (with
+// optional headers), in a text or JSON format
+type CredentialSource struct {
+	File    string            `json:"file,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Format  *CredentialFormat `json:"format,omitempty"`
+}
+
+// CredentialFormat struct represents the format of a
+// CredentialSource. For the json type, the subject token is read
+// from the SubjectTokenFieldName field
+type CredentialFormat struct {
+	Type                  string `json:"type"`
+	SubjectTokenFieldName string `json:"subject_token_field_name,omitempty"`
+}
+
+// ReadExternalAccount function will read and validate the input
+// external_account credential configuration file
+func ReadExternalAccount(file string) (*ExternalAccount, error) {
+	f, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &ExternalAccount{}
+	if err := json.Unmarshal(f, e); err != nil {
+		return nil, errors.New(`Invalid external_account file ` + file + `: ` + err.Error())
+	}
+
+	if e.Type != CredentialTypeExternalAccount {
+		return nil, errors.New(`Invalid credential type: ` + e.Type + ` - expected ` + CredentialTypeExternalAccount)
+	}
+	if e.Audience == "" {
+		return nil, errors.New(`Audience not defined in ` + file + ` - mandatory field`)
+	}
+	if e.SubjectTokenType == "" {
+		return nil, errors.New(`Subject token type not defined in ` + file + ` - mandatory field`)
+	}
+	if e.CredentialSource == nil {
+		return nil, errors.New(`Credential source not defined in ` + file + ` - mandatory field`)
+	}
+	if e.TokenURL == "" {
+		e.TokenURL = GoogleSTSURL
+	}
+
+	e.AccessToken = &AccessToken{}
+	return e, nil
+}
+
+// SubjectToken method will retrieve the ExternalAccount's subject
+// token from its credential source
+func (e *ExternalAccount) SubjectToken() (string, error) {
+	var body []byte
+	var err error
+
+	switch {
+	case e.CredentialSource.File != "":
+		body, err = ioutil.ReadFile(e.CredentialSource.File)
+	case e.CredentialSource.URL != "":
+		body, err = e.CredentialSource.fetch()
+	default:
+		return "", errors.New(`Unsupported credential source - only file and url sources are supported`)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return e.CredentialSource.parse(body)
+}
+
+func (c *CredentialSource) fetch() ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, c.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range c.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, errors.New(`Request to ` + c.URL + ` failed: HTTP ` + strconv.Itoa(resp.StatusCode) + `
+
+` + string(body))
+	}
+
+	return body, nil
+}
+
+func (c *CredentialSource) parse(body []byte) (string, error) {
+	if c.Format == nil || c.Format.Type == "" || c.Format.Type == credentialFormatText {
+		if token := strings.TrimSpace(string(body)); token != "" {
+			return token, nil
+		}
+		return "", errors.New(`No subject token found in the credential source`)
+	}
+
+	if c.Format.Type != credentialFormatJSON {
+		return "", errors.New(`Invalid credential source format: ` + c.Format.Type + ` - must be either text or json`)
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return "", errors.New(`Invalid JSON credential source: ` + err.Error())
+	}
+
+	token, ok := fields[c.Format.SubjectTokenFieldName].(string)
+	if !ok || token == "" {
+		return "", errors.New(`No subject token found in the credential source's ` + c.Format.SubjectTokenFieldName + ` field`)
+	}
+	return token, nil
+}
+
+// Auth method will exchange the ExternalAccount's subject token for
+// a Google Access Token, with the input space-delimited scopes. If a
+// service account impersonation URL is set, the federated token is
+// used to impersonate that service account, which is issued the
+// requested scopes instead
+func (e *ExternalAccount) Auth(scopes string) error {
+	if scopes == "" {
+		scopes = CloudPlatformScope
+	}
+
+	subjectToken, err := e.SubjectToken()
+	if err != nil {
+		return err
+	}
+
+	exchange, err := NewTokenExchange(e.TokenURL, subjectToken, e.SubjectTokenType)
+	if err != nil {
+		return err
+	}
+	exchange.SetRequestedTokenType("access_token")
+	exchange.Audience = e.Audience
+	exchange.Scopes = scopes
+
+	if e.ServiceAccountImpersonationURL != "" {
+		exchange.Scopes = CloudPlatformScope
+	}
+
+	if err := exchange.Exchange(); err != nil {
+		return err
+	}
+
+	if e.ServiceAccountImpersonationURL == "" {
+		e.AccessToken = exchange.AccessToken
+		return nil
+	}
+
+	imp, err := e.impersonation(scopes)
+	if err != nil {
+		return err
+	}
+
+	if err := imp.GenerateAccessToken(exchange.AccessToken.Token); err != nil {
+		return err
+	}
+
+	e.AccessToken = imp.AccessToken
+	return nil
+}
+
+// impersonation method will create an Impersonation object from the
+// ExternalAccount's service account impersonation URL and settings
+func (e *ExternalAccount) impersonation(scopes string) (*Impersonation, error) {
+	idx := strings.Index(e.ServiceAccountImpersonationURL, serviceAccountPrefix)
+	if idx < 0 || !strings.HasSuffix(e.ServiceAccountImpersonationURL, `:generateAccessToken`) {
+		return nil, errors.New(`Invalid service account impersonation URL: ` + e.ServiceAccountImpersonationURL)
+	}
+
+	target := strings.TrimSuffix(e.ServiceAccountImpersonationURL[idx+len(serviceAccountPrefix):], `:generateAccessToken`)
+
+	imp, err := NewImpersonation(target, scopes)
+	if err != nil {
+		return nil, err
+	}
+	imp.Endpoint = e.ServiceAccountImpersonationURL[:idx]
+
+	if e.ServiceAccountImpersonation != nil {
+		imp.SetLifetime(time.Duration(e.ServiceAccountImpersonation.TokenLifetimeSeconds) * time.Second)
+	}
+
+	return imp, nil
+}
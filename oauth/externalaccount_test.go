@@ -0,0 +1,134 @@
+package oauth
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExternalAccount(t *testing.T) {
+	dir := t.TempDir()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/subject":
+			if r.Header.Get("Metadata") != "True" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"access_token":"SubjectToken"}`))
+
+		case "/sts":
+			r.ParseForm()
+			if r.PostForm.Get("subject_token") != "SubjectToken" || r.PostForm.Get("audience") != "//iam.googleapis.com/pool" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"invalid_grant","error_description":"invalid subject token"}`))
+				return
+			}
+			w.Write([]byte(`{"access_token":"FederatedToken","issued_token_type":"` + tokenTypeURIPrefix + `access_token","token_type":"Bearer","expires_in":3600}`))
+
+		case "/v1/" + serviceAccountPrefix + "target@project.iam.gserviceaccount.com:generateAccessToken":
+			if r.Header.Get("Authorization") != "Bearer FederatedToken" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"accessToken":"ImpersonatedToken","expireTime":"` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `"}`))
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(tokenFile, []byte("SubjectToken\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		account *ExternalAccount
+		want    string
+		ok      bool
+	}{
+		{
+			name: "file source",
+			account: &ExternalAccount{
+				CredentialSource: &CredentialSource{File: tokenFile},
+			},
+			want: "FederatedToken",
+			ok:   true,
+		}, {
+			name: "url source",
+			account: &ExternalAccount{
+				CredentialSource: &CredentialSource{
+					URL:     server.URL + "/subject",
+					Headers: map[string]string{"Metadata": "True"},
+					Format:  &CredentialFormat{Type: "json", SubjectTokenFieldName: "access_token"},
+				},
+			},
+			want: "FederatedToken",
+			ok:   true,
+		}, {
+			name: "impersonation",
+			account: &ExternalAccount{
+				ServiceAccountImpersonationURL: server.URL + "/v1/" + serviceAccountPrefix + "target@project.iam.gserviceaccount.com:generateAccessToken",
+				CredentialSource:               &CredentialSource{File: tokenFile},
+			},
+			want: "ImpersonatedToken",
+			ok:   true,
+		}, {
+			name: "invalid json field",
+			account: &ExternalAccount{
+				CredentialSource: &CredentialSource{
+					URL:     server.URL + "/subject",
+					Headers: map[string]string{"Metadata": "True"},
+					Format:  &CredentialFormat{Type: "json", SubjectTokenFieldName: "id_token"},
+				},
+			},
+			ok: false,
+		}, {
+			name: "unsupported source",
+			account: &ExternalAccount{
+				CredentialSource: &CredentialSource{},
+			},
+			ok: false,
+		},
+	}
+
+	for _, test := range tests {
+		test.account.Type = CredentialTypeExternalAccount
+		test.account.Audience = "//iam.googleapis.com/pool"
+		test.account.SubjectTokenType = tokenTypeURIPrefix + "jwt"
+		test.account.TokenURL = server.URL + "/sts"
+
+		buf, err := json.Marshal(test.account)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		file := filepath.Join(dir, "external_account.json")
+		if err := ioutil.WriteFile(file, buf, 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		account, err := ReadExternalAccount(file)
+		if err != nil {
+			t.Fatalf(`TestExternalAccount(%s) = %v, expected no error`, test.name, err)
+		}
+
+		err = account.Auth("")
+		if (err == nil) != test.ok {
+			t.Errorf(`TestExternalAccount(%s) = %v, expected error to be %v`, test.name, err, !test.ok)
+			continue
+		}
+
+		if test.ok && account.AccessToken.Token != test.want {
+			t.Errorf(`TestExternalAccount(%s) = %q, expected result to be %q`, test.name, account.AccessToken.Token, test.want)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package oauth
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// GoogleIssuer is the issuer of Google's ID Tokens
+	GoogleIssuer string = `https://accounts.google.com`
+
+	googleIssuerLegacy string        = `accounts.google.com`
+	idTokenLeeway      time.Duration = time.Minute
+)
+
+// ErrNoIssuer is returned when an ID Token can't be verified, as the
+// ClientID's Endpoint has no issuer to check it against
+var ErrNoIssuer = errors.New(`Issuer not set; pass -issuer or use discovery`)
+
+// IDTokenClaims struct represents the claims of an OpenID Connect
+// ID Token
+type IDTokenClaims struct {
+	Issuer          string   `json:"iss,omitempty"`
+	Subject         string   `json:"sub,omitempty"`
+	Audience        Audience `json:"aud,omitempty"`
+	AuthorizedParty string   `json:"azp,omitempty"`
+	Expiry          int64    `json:"exp,omitempty"`
+	IssuedAt        int64    `json:"iat,omitempty"`
+	Nonce           string   `json:"nonce,omitempty"`
+	Email           string   `json:"email,omitempty"`
+	EmailVerified   bool     `json:"email_verified,omitempty"`
+	HostedDomain    string   `json:"hd,omitempty"`
+	Name            string   `json:"name,omitempty"`
+}
+
+// JWKS struct represents a JSON Web Key Set, as served by a
+// provider's jwks_uri
+type JWKS struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// JWK struct represents a JSON Web Key (RFC 7517). Only RSA keys
+// are supported
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	Use       string `json:"use,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// jwtHeaderFull struct represents the header fields of a received
+// JWT which are relevant to verify its signature
+type jwtHeaderFull struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+// FetchJWKS function will retrieve the JSON Web Key Set from the
+// input URL
+func FetchJWKS(jwksURL string) (*JWKS, error) {
+	resp, err := http.Get(jwksURL)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(`Unable to fetch JWKS from ` + jwksURL + `: HTTP ` + strconv.Itoa(resp.StatusCode))
+	}
+
+	jwks := &JWKS{}
+	if err := json.Unmarshal(body, jwks); err != nil {
+		return nil, err
+	}
+
+	return jwks, nil
+}
+
+// Find method returns the JWKS' key with the input key ID. If no key
+// ID is set and the set only holds one key, that key is returned
+func (j *JWKS) Find(kid string) (*JWK, error) {
+	if kid == "" && len(j.Keys) == 1 {
+		return j.Keys[0], nil
+	}
+
+	for _, key := range j.Keys {
+		if key.KeyID == kid {
+			return key, nil
+		}
+	}
+	return nil, errors.New(`No key found in the JWKS with key ID: ` + kid)
+}
+
+// PublicKey method returns the RSA public key described by the JWK
+func (k *JWK) PublicKey() (*rsa.PublicKey, error) {
+	if k.KeyType != "RSA" {
+		return nil, errors.New(`Unsupported JWK key type: ` + k.KeyType)
+	}
+
+	n, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.N, "="))
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.E, "="))
+	if err != nil {
+		return nil, err
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+// DecodeIDToken function will decode the input ID Token's claims,
+// without verifying its signature
+func DecodeIDToken(token string) (*IDTokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New(`Invalid ID Token: expected a JWT with 3 parts`)
+	}
+
+	claims := &IDTokenClaims{}
+	if err := decodeSegment(parts[1], claims); err != nil {
+		return nil, errors.New(`Invalid ID Token claims: ` + err.Error())
+	}
+
+	return claims, nil
+}
+
+// VerifyIDToken function will verify the input ID Token's signature
+// against the input JWKS, and check that it was issued by the input
+// issuer, for the input audience (the Client ID), and that it isn't
+// expired. If a nonce is set, it must match the ID Token's nonce
+func VerifyIDToken(token string, jwks *JWKS, issuer, audience, nonce string) (*IDTokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New(`Invalid ID Token: expected a JWT with 3 parts`)
+	}
+
+	header := &jwtHeaderFull{}
+	if err := decodeSegment(parts[0], header); err != nil {
+		return nil, errors.New(`Invalid ID Token header: ` + err.Error())
+	}
+	if header.Algorithm != "RS256" {
+		return nil, errors.New(`Unsupported ID Token signing algorithm: ` + header.Algorithm)
+	}
+
+	key, err := jwks.Find(header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := key.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New(`Invalid ID Token signature: ` + err.Error())
+	}
+	hash := sha256.Sum256([]byte(parts[0] + `.` + parts[1]))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, hash[:], sig); err != nil {
+		return nil, errors.New(`Invalid ID Token signature: ` + err.Error())
+	}
+
+	claims, err := DecodeIDToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := claims.Validate(issuer, audience, nonce); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// Validate method will check the IDTokenClaims' issuer, audience,
+// expiry and (if set) nonce against the input values
+func (c *IDTokenClaims) Validate(issuer, audience, nonce string) error {
+	if !matchIssuer(issuer, c.Issuer) {
+		return errors.New(`Invalid ID Token issuer: expected ` + issuer + `, got ` + c.Issuer)
+	}
+
+	found := false
+	for _, aud := range c.Audience {
+		if aud == audience {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return errors.New(`Invalid ID Token audience: expected ` + audience + `, got ` + c.Audience.String())
+	}
+
+	now := time.Now()
+	if time.Unix(c.Expiry, 0).Add(idTokenLeeway).Before(now) {
+		return errors.New(`Invalid ID Token: expired at ` + time.Unix(c.Expiry, 0).Format(time.RFC3339))
+	}
+	if time.Unix(c.IssuedAt, 0).Add(-idTokenLeeway).After(now) {
+		return errors.New(`Invalid ID Token: issued in the future, at ` + time.Unix(c.IssuedAt, 0).Format(time.RFC3339))
+	}
+
+	if nonce != "" && c.Nonce != nonce {
+		return errors.New(`Invalid ID Token nonce: expected ` + nonce + `, got ` + c.Nonce)
+	}
+
+	return nil
+}
+
+// Print method will output the IDTokenClaims' set values
+func (c *IDTokenClaims) Print() {
+	var b strings.Builder
+	line := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%s: %s\n", key, value)
+		}
+	}
+
+	b.WriteString("====\n")
+	line("Issuer", c.Issuer)
+	line("Subject", c.Subject)
+	line("Audience", c.Audience.String())
+	line("Email", c.Email)
+	if c.Email != "" {
+		line("Email Verified", strconv.FormatBool(c.EmailVerified))
+	}
+	line("Hosted Domain", c.HostedDomain)
+	line("Name", c.Name)
+	line("Expiry", time.Unix(c.Expiry, 0).Format(time.RFC3339))
+	b.WriteString("====")
+
+	fmt.Println(b.String())
+}
+
+// VerifyIDToken method will verify the ID Token returned along with
+// the ClientID's Access Token, against its provider's JWKS. The nonce
+// is only checked when one was sent in the authorization request
+func (c *ClientID) VerifyIDToken() (*IDTokenClaims, error) {
+	if c.AccessToken.IDToken == "" {
+		return nil, errors.New(`No ID Token in the token response - the openid scope must be requested`)
+	}
+
+	// custom endpoints don't carry an issuer, unless discovered
+	if c.Endpoint.Issuer == "" {
+		return nil, ErrNoIssuer
+	}
+
+	jwks, err := FetchJWKS(c.Endpoint.JWKSURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return VerifyIDToken(c.AccessToken.IDToken, jwks, c.Endpoint.Issuer, c.GetID(), c.GetNonce())
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	buf, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, v)
+}
+
+// matchIssuer function checks whether the input issuers match,
+// accepting both forms of Google's issuer
+func matchIssuer(want, got string) bool {
+	if want == GoogleIssuer && got == googleIssuerLegacy {
+		return true
+	}
+	return strings.TrimSuffix(want, "/") == strings.TrimSuffix(got, "/")
+}
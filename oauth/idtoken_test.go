@@ -0,0 +1,149 @@
+package oauth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func signIDToken(t *testing.T, key *rsa.PrivateKey, kid string, claims *IDTokenClaims) string {
+	header, _ := json.Marshal(&jwtHeaderKeyID{Algorithm: "RS256", Type: "JWT", KeyID: kid})
+	body, _ := json.Marshal(claims)
+
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(body)
+	hash := sha256.Sum256([]byte(input))
+
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwks := &JWKS{
+		Keys: []*JWK{
+			{
+				KeyType: "RSA",
+				KeyID:   "SomeKeyID",
+				N:       base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+				E:       base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+			},
+		},
+	}
+
+	valid := func() *IDTokenClaims {
+		return &IDTokenClaims{
+			Issuer:   GoogleIssuer,
+			Subject:  "1234567890",
+			Audience: Audience{"ClientID"},
+			Expiry:   time.Now().Add(time.Hour).Unix(),
+			IssuedAt: time.Now().Unix(),
+			Nonce:    "SomeNonce",
+			Email:    "user@example.com",
+		}
+	}
+
+	tests := []struct {
+		name   string
+		key    *rsa.PrivateKey
+		kid    string
+		claims func(c *IDTokenClaims)
+		ok     bool
+	}{
+		{
+			name:   "valid",
+			key:    key,
+			kid:    "SomeKeyID",
+			claims: func(c *IDTokenClaims) {},
+			ok:     true,
+		}, {
+			name:   "legacy Google issuer",
+			key:    key,
+			kid:    "SomeKeyID",
+			claims: func(c *IDTokenClaims) { c.Issuer = googleIssuerLegacy },
+			ok:     true,
+		}, {
+			name:   "wrong signing key",
+			key:    otherKey,
+			kid:    "SomeKeyID",
+			claims: func(c *IDTokenClaims) {},
+			ok:     false,
+		}, {
+			name:   "unknown key ID",
+			key:    key,
+			kid:    "OtherKeyID",
+			claims: func(c *IDTokenClaims) {},
+			ok:     false,
+		}, {
+			name:   "wrong issuer",
+			key:    key,
+			kid:    "SomeKeyID",
+			claims: func(c *IDTokenClaims) { c.Issuer = "https://attacker.example.com" },
+			ok:     false,
+		}, {
+			name:   "wrong audience",
+			key:    key,
+			kid:    "SomeKeyID",
+			claims: func(c *IDTokenClaims) { c.Audience = Audience{"OtherClientID"} },
+			ok:     false,
+		}, {
+			name:   "expired",
+			key:    key,
+			kid:    "SomeKeyID",
+			claims: func(c *IDTokenClaims) { c.Expiry = time.Now().Add(-time.Hour).Unix() },
+			ok:     false,
+		}, {
+			name:   "wrong nonce",
+			key:    key,
+			kid:    "SomeKeyID",
+			claims: func(c *IDTokenClaims) { c.Nonce = "OtherNonce" },
+			ok:     false,
+		},
+	}
+
+	for _, test := range tests {
+		claims := valid()
+		test.claims(claims)
+		token := signIDToken(t, test.key, test.kid, claims)
+
+		got, err := VerifyIDToken(token, jwks, GoogleIssuer, "ClientID", "SomeNonce")
+		if (err == nil) != test.ok {
+			t.Errorf(`TestVerifyIDToken(%q) = %v, expected error to be %v`, test.name, err, !test.ok)
+			continue
+		}
+		if err == nil && got.Email != "user@example.com" {
+			t.Errorf(`TestVerifyIDToken(%q) = %q, expected email to be %q`, test.name, got.Email, "user@example.com")
+		}
+	}
+}
+
+func TestClientIDVerifyIDTokenIssuer(t *testing.T) {
+	clientID, _ := NewClientID("ClientID", "ClientSecret", "openid", "")
+	clientID.Endpoint = &Endpoint{
+		AuthURL:  "https://auth.example.com/authorize",
+		TokenURL: "https://auth.example.com/token",
+		JWKSURL:  "https://auth.example.com/jwks",
+	}
+	clientID.AccessToken.IDToken = "SomeIDToken"
+
+	_, err := clientID.VerifyIDToken()
+	if err != ErrNoIssuer {
+		t.Errorf(`TestClientIDVerifyIDTokenIssuer() = %v, expected an issuer not set error`, err)
+	}
+}
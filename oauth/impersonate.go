@@ -0,0 +1,205 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// IAMCredentialsURL is the base URL for Google's IAM Credentials API
+	IAMCredentialsURL string = `https://iamcredentials.googleapis.com/v1/`
+
+	// CloudPlatformScope is the scope required by the source credentials
+	// to call the IAM Credentials API
+	CloudPlatformScope string = `https://www.googleapis.com/auth/cloud-platform`
+
+	impersonationLifetime time.Duration = time.Hour
+	serviceAccountPrefix  string        = `projects/-/serviceAccounts/`
+)
+
+// Impersonation struct represents a request to generate short-lived
+// credentials for a target service account, through the IAM
+// Credentials API, authenticated with the source credentials' Access
+// Token. Delegates lists the service accounts in the delegation chain
+// (if any), each of which must be able to impersonate the next one
+type Impersonation struct {
+	Endpoint    string
+	Target      string
+	Delegates   []string
+	Scopes      []string
+	Lifetime    time.Duration
+	AccessToken *AccessToken
+}
+
+type generateAccessTokenRequest struct {
+	Delegates []string `json:"delegates,omitempty"`
+	Scope     []string `json:"scope"`
+	Lifetime  string   `json:"lifetime,omitempty"`
+}
+
+type generateAccessTokenResponse struct {
+	AccessToken string `json:"accessToken"`
+	ExpireTime  string `json:"expireTime"`
+}
+
+// NewImpersonation function will create an Impersonation object for
+// the input target service account email, with the input
+// space-delimited scopes. If no scopes are set, the cloud-platform
+// scope is used
+func NewImpersonation(target, scopes string) (*Impersonation, error) {
+	if target == "" {
+		return nil, errors.New(`Target service account not defined - mandatory field`)
+	}
+
+	i := &Impersonation{
+		Endpoint:    IAMCredentialsURL,
+		Target:      target,
+		Scopes:      strings.Fields(scopes),
+		Lifetime:    impersonationLifetime,
+		AccessToken: &AccessToken{},
+	}
+
+	if len(i.Scopes) == 0 {
+		i.Scopes = []string{CloudPlatformScope}
+	}
+
+	return i, nil
+}
+
+// SetDelegates method will define the delegation chain for the
+// Impersonation object, from a comma-separated list of service
+// account emails
+func (i *Impersonation) SetDelegates(input string) {
+	i.Delegates = nil
+
+	for _, d := range strings.Split(input, ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			i.Delegates = append(i.Delegates, serviceAccountName(d))
+		}
+	}
+	return
+}
+
+// SetLifetime method will define the lifetime of the credentials
+// issued for the Impersonation object. A lifetime of 0 will use the
+// default lifetime of 1 hour
+func (i *Impersonation) SetLifetime(lifetime time.Duration) {
+	if lifetime <= 0 {
+		lifetime = impersonationLifetime
+	}
+	i.Lifetime = lifetime
+	return
+}
+
+// GenerateAccessToken method will request an Access Token for the
+// target service account, authenticated with the input source Access
+// Token, storing it in the Impersonation's AccessToken
+func (i *Impersonation) GenerateAccessToken(source string) error {
+	if source == "" {
+		return errors.New(`Source Access Token not defined - mandatory field`)
+	}
+
+	body, err := postJSON(i.methodURL("generateAccessToken"), source, &generateAccessTokenRequest{
+		Delegates: i.Delegates,
+		Scope:     i.Scopes,
+		Lifetime:  strconv.FormatInt(int64(i.Lifetime/time.Second), 10) + "s",
+	})
+	if err != nil {
+		return err
+	}
+
+	resp := &generateAccessTokenResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return err
+	}
+
+	if resp.AccessToken == "" {
+		return errors.New(`No Access Token found in response:
+
+` + string(body))
+	}
+
+	i.AccessToken = &AccessToken{
+		Token:     resp.AccessToken,
+		Scopes:    strings.Join(i.Scopes, " "),
+		TokenType: "Bearer",
+	}
+
+	if expiry, err := time.Parse(time.RFC3339, resp.ExpireTime); err == nil {
+		i.AccessToken.Expiry = int(time.Until(expiry) / time.Second)
+	}
+
+	return nil
+}
+
+type generateIDTokenRequest struct {
+	Delegates    []string `json:"delegates,omitempty"`
+	Audience     string   `json:"audience"`
+	IncludeEmail bool     `json:"includeEmail,omitempty"`
+}
+
+type generateIDTokenResponse struct {
+	Token string `json:"token"`
+}
+
+// GenerateIDToken method will request a Google-signed ID Token for
+// the target service account, for the input audience, authenticated
+// with the input source Access Token. The ID Token is stored in the
+// Impersonation's AccessToken, and includes the service account's
+// email in its claims
+func (i *Impersonation) GenerateIDToken(source, audience string) error {
+	if source == "" {
+		return errors.New(`Source Access Token not defined - mandatory field`)
+	}
+	if audience == "" {
+		return errors.New(`Target audience not defined - mandatory field`)
+	}
+
+	body, err := postJSON(i.methodURL("generateIdToken"), source, &generateIDTokenRequest{
+		Delegates:    i.Delegates,
+		Audience:     audience,
+		IncludeEmail: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp := &generateIDTokenResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return err
+	}
+
+	if resp.Token == "" {
+		return errors.New(`No ID Token found in response:
+
+` + string(body))
+	}
+
+	i.AccessToken = &AccessToken{
+		IDToken: resp.Token,
+	}
+
+	return nil
+}
+
+// methodURL method returns the IAM Credentials API URL for the input
+// method, on the target service account
+func (i *Impersonation) methodURL(method string) string {
+	endpoint := i.Endpoint
+	if !strings.HasSuffix(endpoint, "/") {
+		endpoint += "/"
+	}
+	return endpoint + serviceAccountName(i.Target) + `:` + method
+}
+
+// serviceAccountName function returns the IAM resource name for the
+// input service account email. Resource names are returned as-is
+func serviceAccountName(input string) string {
+	if strings.HasPrefix(input, "projects/") {
+		return input
+	}
+	return serviceAccountPrefix + input
+}
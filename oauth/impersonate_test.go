@@ -0,0 +1,173 @@
+package oauth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestImpersonation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer SourceToken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"code":401,"message":"Request had invalid authentication credentials.","status":"UNAUTHENTICATED"}}`))
+			return
+		}
+		if r.URL.Path != "/"+serviceAccountPrefix+"target@project.iam.gserviceaccount.com:generateAccessToken" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":{"code":403,"message":"Permission 'iam.serviceAccounts.getAccessToken' denied","status":"PERMISSION_DENIED"}}`))
+			return
+		}
+
+		req := &generateAccessTokenRequest{}
+		json.NewDecoder(r.Body).Decode(req)
+
+		if len(req.Scope) == 0 || req.Lifetime != "1800s" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":400,"message":"Invalid request","status":"INVALID_ARGUMENT"}}`))
+			return
+		}
+		for _, d := range req.Delegates {
+			if d != serviceAccountPrefix+"delegate@project.iam.gserviceaccount.com" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":{"code":400,"message":"Invalid delegate","status":"INVALID_ARGUMENT"}}`))
+				return
+			}
+		}
+
+		w.Write([]byte(`{"accessToken":"ImpersonatedToken","expireTime":"` + time.Now().Add(30*time.Minute).UTC().Format(time.RFC3339) + `"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		source    string
+		target    string
+		delegates string
+		ok        bool
+	}{
+		{
+			source: "SourceToken",
+			target: "target@project.iam.gserviceaccount.com",
+			ok:     true,
+		}, {
+			source:    "SourceToken",
+			target:    "target@project.iam.gserviceaccount.com",
+			delegates: "delegate@project.iam.gserviceaccount.com, " + serviceAccountPrefix + "delegate@project.iam.gserviceaccount.com",
+			ok:        true,
+		}, {
+			source:    "SourceToken",
+			target:    "target@project.iam.gserviceaccount.com",
+			delegates: "other@project.iam.gserviceaccount.com",
+			ok:        false,
+		}, {
+			source: "SourceToken",
+			target: "other@project.iam.gserviceaccount.com",
+			ok:     false,
+		}, {
+			source: "InvalidToken",
+			target: "target@project.iam.gserviceaccount.com",
+			ok:     false,
+		}, {
+			source: "",
+			target: "target@project.iam.gserviceaccount.com",
+			ok:     false,
+		},
+	}
+
+	for _, test := range tests {
+		imp, err := NewImpersonation(test.target, "")
+		if err != nil {
+			t.Fatalf(`TestImpersonation(%q) = %v, expected no error`, test.target, err)
+		}
+		imp.Endpoint = server.URL
+		imp.SetDelegates(test.delegates)
+		imp.SetLifetime(30 * time.Minute)
+
+		err = imp.GenerateAccessToken(test.source)
+		if (err == nil) != test.ok {
+			t.Errorf(`TestImpersonation(%q, %q, %q) = %v, expected error to be %v`, test.source, test.target, test.delegates, err, !test.ok)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		if imp.AccessToken.Token != "ImpersonatedToken" {
+			t.Errorf(`TestImpersonation(%q, %q, %q) = %q, expected result to be %q`, test.source, test.target, test.delegates, imp.AccessToken.Token, "ImpersonatedToken")
+		}
+		if imp.AccessToken.Expiry <= 0 || imp.AccessToken.Expiry > 1800 {
+			t.Errorf(`TestImpersonation(%q, %q, %q) = %v, expected expiry within the lifetime`, test.source, test.target, test.delegates, imp.AccessToken.Expiry)
+		}
+		if imp.AccessToken.Scopes != CloudPlatformScope {
+			t.Errorf(`TestImpersonation(%q, %q, %q) = %q, expected default scope %q`, test.source, test.target, test.delegates, imp.AccessToken.Scopes, CloudPlatformScope)
+		}
+	}
+}
+
+func TestImpersonationIDToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer SourceToken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"code":401,"message":"Request had invalid authentication credentials.","status":"UNAUTHENTICATED"}}`))
+			return
+		}
+		if r.URL.Path != "/"+serviceAccountPrefix+"target@project.iam.gserviceaccount.com:generateIdToken" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		req := &generateIDTokenRequest{}
+		json.NewDecoder(r.Body).Decode(req)
+
+		if !req.IncludeEmail {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":400,"message":"Invalid request","status":"INVALID_ARGUMENT"}}`))
+			return
+		}
+
+		w.Write([]byte(`{"token":"IDToken-` + req.Audience + `"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		source   string
+		audience string
+		want     string
+		ok       bool
+	}{
+		{
+			source:   "SourceToken",
+			audience: "https://service.run.app",
+			want:     "IDToken-https://service.run.app",
+			ok:       true,
+		}, {
+			source:   "InvalidToken",
+			audience: "https://service.run.app",
+			ok:       false,
+		}, {
+			source:   "SourceToken",
+			audience: "",
+			ok:       false,
+		},
+	}
+
+	for _, test := range tests {
+		imp, err := NewImpersonation("target@project.iam.gserviceaccount.com", "")
+		if err != nil {
+			t.Fatalf(`TestImpersonationIDToken() = %v, expected no error`, err)
+		}
+		imp.Endpoint = server.URL
+
+		err = imp.GenerateIDToken(test.source, test.audience)
+		if (err == nil) != test.ok {
+			t.Errorf(`TestImpersonationIDToken(%q, %q) = %v, expected error to be %v`, test.source, test.audience, err, !test.ok)
+			continue
+		}
+
+		if test.ok && imp.AccessToken.IDToken != test.want {
+			t.Errorf(`TestImpersonationIDToken(%q, %q) = %q, expected result to be %q`, test.source, test.audience, imp.AccessToken.IDToken, test.want)
+		}
+	}
+}
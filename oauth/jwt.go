@@ -33,6 +33,16 @@ type JWTClaim struct {
 	Audience   string `json:"aud,omitempty"`
 	Expiry     int64  `json:"exp,omitempty"`
 	Issued     int64  `json:"iat,omitempty"`
+	JWTID      string `json:"jti,omitempty"`
+
+	TargetAudience string `json:"target_audience,omitempty"`
+}
+
+// jwtHeaderKeyID struct represents a JWT header with a key ID
+type jwtHeaderKeyID struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyID     string `json:"kid"`
 }
 
 // SetExpiry method defines the Token's issuing and expiry time
@@ -42,6 +52,14 @@ func (c *JWTClaim) SetExpiry() {
 	return
 }
 
+// SetLifetime method defines the Token's issuing time, and an expiry
+// time after the input lifetime
+func (c *JWTClaim) SetLifetime(lifetime time.Duration) {
+	c.Issued = time.Now().Unix()
+	c.Expiry = c.Issued + int64(lifetime/time.Second)
+	return
+}
+
 // Sign method will create a signature for the JWT
 func (j *JWT) Sign(pkey string) ([]byte, error) {
 
@@ -107,10 +125,10 @@ func b64(input interface{}) (string, error) {
 		if err != nil {
 			return "", err
 		}
-		return base64.URLEncoding.EncodeToString(buf), nil
+		return base64.RawURLEncoding.EncodeToString(buf), nil
 
 	case []byte:
-		return base64.URLEncoding.EncodeToString(t), nil
+		return base64.RawURLEncoding.EncodeToString(t), nil
 
 	default:
 		return "", errors.New("Invalid data type provided")
@@ -130,6 +148,22 @@ func (j *JWT) InitHeader() {
 	return
 }
 
+// SetKeyID method defines the JWT's header value with the input
+// key ID, so the verifier can select the right public key
+func (j *JWT) SetKeyID(kid string) error {
+	header, err := json.Marshal(&jwtHeaderKeyID{
+		Algorithm: "RS256",
+		Type:      "JWT",
+		KeyID:     kid,
+	})
+	if err != nil {
+		return err
+	}
+
+	j.Header = header
+	return nil
+}
+
 // SetIssuer method defines the JWTClaim's issuer value
 func (c *JWTClaim) SetIssuer(input string) {
 	c.Issuer = input
@@ -154,6 +188,20 @@ func (c *JWTClaim) SetAudience(input string) {
 	return
 }
 
+// SetTargetAudience method defines the JWTClaim's target audience
+// value, requesting an ID Token for the input audience instead of an
+// Access Token
+func (c *JWTClaim) SetTargetAudience(input string) {
+	c.TargetAudience = input
+	return
+}
+
+// SetJWTID method defines the JWTClaim's unique identifier value
+func (c *JWTClaim) SetJWTID(input string) {
+	c.JWTID = input
+	return
+}
+
 // GetOutput method returns the complete JWT string
 func (j *JWT) GetOutput() string {
 	return string(j.Output)
@@ -0,0 +1,170 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	loopbackHost    string        = `127.0.0.1`
+	loopbackTimeout time.Duration = 5 * time.Minute
+
+	loopbackSuccessPage string = `<html>
+<head><title>GoAuth</title></head>
+<body>
+<h3>Authorization complete</h3>
+<p>You may now close this window and return to the terminal.</p>
+</body>
+</html>`
+
+	loopbackErrorPage string = `<html>
+<head><title>GoAuth</title></head>
+<body>
+<h3>Authorization failed</h3>
+<p>%s</p>
+</body>
+</html>`
+)
+
+// ErrLoopbackTimeout is returned when the user does not complete
+// the consent screen before the loopback listener times out
+var ErrLoopbackTimeout = errors.New(`Timed out waiting for the authorization redirect`)
+
+// Loopback struct represents a temporary HTTP listener on the
+// loopback interface, used to capture the Access Code from the
+// authorization server's redirect. If State is set, it is verified
+// against the redirect's state parameter
+type Loopback struct {
+	Port     int
+	Timeout  time.Duration
+	State    string
+	listener net.Listener
+	server   *http.Server
+	result   chan *loopbackResult
+}
+
+type loopbackResult struct {
+	code string
+	err  error
+}
+
+// NewLoopback function will start listening on the loopback
+// interface, on the input port. A port of 0 will let the OS pick
+// a random available port, and a timeout of 0 will use the default
+// timeout of 5 minutes
+func NewLoopback(port int, timeout time.Duration) (*Loopback, error) {
+	listener, err := net.Listen("tcp", net.JoinHostPort(loopbackHost, strconv.Itoa(port)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to start loopback listener: %v", err)
+	}
+
+	if timeout <= 0 {
+		timeout = loopbackTimeout
+	}
+
+	l := &Loopback{
+		Port:     listener.Addr().(*net.TCPAddr).Port,
+		Timeout:  timeout,
+		listener: listener,
+		result:   make(chan *loopbackResult, 1),
+	}
+	l.server = &http.Server{Handler: l}
+
+	go l.server.Serve(listener)
+
+	return l, nil
+}
+
+// ServeHTTP method will handle the authorization server's redirect,
+// capturing either the Access Code or the returned error
+func (l *Loopback) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+
+	if err := redirectError(query); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, loopbackErrorPage, err.Error())
+		l.send(&loopbackResult{err: err})
+		return
+	}
+
+	if l.State != "" {
+		if err := CheckState(l.State, query.Get("state")); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, loopbackErrorPage, err.Error())
+			l.send(&loopbackResult{err: err})
+			return
+		}
+	}
+
+	// requests without an Access Code (like a browser prefetch or a
+	// reload) are rejected, while waiting for the actual redirect
+	code := query.Get("code")
+	if code == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, loopbackErrorPage, `No Access Code found in the request`)
+		return
+	}
+
+	fmt.Fprint(w, loopbackSuccessPage)
+	l.send(&loopbackResult{code: code})
+}
+
+func redirectError(query url.Values) error {
+	e := query.Get("error")
+	if e == "" {
+		return nil
+	}
+
+	if desc := query.Get("error_description"); desc != "" {
+		return errors.New(`Authorization denied: ` + e + ` - ` + desc)
+	}
+	return errors.New(`Authorization denied: ` + e)
+}
+
+func (l *Loopback) send(r *loopbackResult) {
+	select {
+	case l.result <- r:
+	default:
+	}
+}
+
+// Wait method will block until the Access Code is received, or
+// until the Loopback's timeout is reached. The listener is shut
+// down before returning
+func (l *Loopback) Wait() (string, error) {
+	defer l.Close()
+
+	select {
+	case r := <-l.result:
+		return r.code, r.err
+	case <-time.After(l.Timeout):
+		return "", ErrLoopbackTimeout
+	}
+}
+
+// Close method will shut down the Loopback's HTTP listener, allowing
+// any in-flight response (like the success page) to be delivered
+func (l *Loopback) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	l.server.Shutdown(ctx)
+	return
+}
+
+// GetRedirectURI method returns the redirect URI pointing to the
+// Loopback's listener
+func (l *Loopback) GetRedirectURI() string {
+	return `http://` + net.JoinHostPort(loopbackHost, strconv.Itoa(l.Port))
+}
@@ -0,0 +1,103 @@
+package oauth
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestLoopbackWait(t *testing.T) {
+	tests := []struct {
+		query string
+		state string
+		want  string
+		ok    bool
+	}{
+		{
+			query: "?code=SomeAccessCode&scope=email",
+			want:  "SomeAccessCode",
+			ok:    true,
+		}, {
+			query: "?error=access_denied",
+			want:  "",
+			ok:    false,
+		}, {
+			query: "",
+			want:  "",
+			ok:    false,
+		}, {
+			query: "?code=SomeAccessCode&state=SomeState",
+			state: "SomeState",
+			want:  "SomeAccessCode",
+			ok:    true,
+		}, {
+			query: "?code=SomeAccessCode&state=OtherState",
+			state: "SomeState",
+			want:  "",
+			ok:    false,
+		},
+	}
+
+	for _, test := range tests {
+		loopback, err := NewLoopback(0, 500*time.Millisecond)
+		if err != nil {
+			t.Fatalf(`TestLoopbackWait(%q) failed to start listener: %v`, test.query, err)
+		}
+		loopback.State = test.state
+
+		go func(uri string) {
+			resp, err := http.Get(uri)
+			if err == nil {
+				resp.Body.Close()
+			}
+		}(loopback.GetRedirectURI() + "/" + test.query)
+
+		code, err := loopback.Wait()
+		if (err == nil) != test.ok {
+			t.Errorf(`TestLoopbackWait(%q) = %v, expected error to be %v`, test.query, err, !test.ok)
+		}
+		if code != test.want {
+			t.Errorf(`TestLoopbackWait(%q) = %q, expected result to be %q`, test.query, code, test.want)
+		}
+	}
+}
+
+func TestLoopbackStrayRequest(t *testing.T) {
+	loopback, err := NewLoopback(0, 5*time.Second)
+	if err != nil {
+		t.Fatalf(`TestLoopbackStrayRequest() failed to start listener: %v`, err)
+	}
+
+	// a request without an Access Code must not end the login
+	resp, err := http.Get(loopback.GetRedirectURI() + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf(`TestLoopbackStrayRequest() = %d, expected status to be %d`, resp.StatusCode, http.StatusBadRequest)
+	}
+
+	go func() {
+		resp, err := http.Get(loopback.GetRedirectURI() + "/?code=SomeAccessCode")
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	code, err := loopback.Wait()
+	if err != nil || code != "SomeAccessCode" {
+		t.Errorf(`TestLoopbackStrayRequest() = %q, %v, expected result to be %q`, code, err, "SomeAccessCode")
+	}
+}
+
+func TestLoopbackTimeout(t *testing.T) {
+	loopback, err := NewLoopback(0, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf(`TestLoopbackTimeout() failed to start listener: %v`, err)
+	}
+
+	if _, err := loopback.Wait(); err != ErrLoopbackTimeout {
+		t.Errorf(`TestLoopbackTimeout() = %v, expected error to be %v`, err, ErrLoopbackTimeout)
+	}
+}
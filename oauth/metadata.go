@@ -0,0 +1,187 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MetadataHost is the GCE metadata server's host name
+	MetadataHost string = `metadata.google.internal`
+
+	// MetadataHostEnv is the environment variable overriding the
+	// metadata server's host (and port)
+	MetadataHostEnv string = `GCE_METADATA_HOST`
+
+	metadataPath          string        = `/computeMetadata/v1/`
+	metadataFlavorHeader  string        = `Metadata-Flavor`
+	metadataFlavor        string        = `Google`
+	metadataDefaultAcct   string        = `default`
+	metadataCheckTimeout  time.Duration = 2 * time.Second
+	metadataClientTimeout time.Duration = 10 * time.Second
+)
+
+// MetadataServer struct represents the GCE metadata server, available
+// on Compute Engine VMs, GKE pods, Cloud Run and other Google Cloud
+// runtimes, which issues tokens for the attached service account.
+// If Scopes are set, they override the instance's scopes (where the
+// runtime allows it, like GKE Workload Identity or Cloud Run)
+type MetadataServer struct {
+	Host        string
+	Account     string
+	Scopes      []string
+	AccessToken *AccessToken
+}
+
+// NewMetadataServer function will create a MetadataServer object for
+// the default service account. The host is read from the
+// GCE_METADATA_HOST environment variable, defaulting to
+// metadata.google.internal
+func NewMetadataServer() *MetadataServer {
+	host := os.Getenv(MetadataHostEnv)
+	if host == "" {
+		host = MetadataHost
+	}
+
+	return &MetadataServer{
+		Host:        host,
+		Account:     metadataDefaultAcct,
+		AccessToken: &AccessToken{},
+	}
+}
+
+// OnGCE method will check whether the metadata server is reachable,
+// returning a boolean. When the host is overridden through the
+// GCE_METADATA_HOST environment variable, it is assumed to be
+func (m *MetadataServer) OnGCE() bool {
+	if os.Getenv(MetadataHostEnv) != "" {
+		return true
+	}
+
+	req, err := http.NewRequest(http.MethodGet, `http://`+m.Host+`/`, nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set(metadataFlavorHeader, metadataFlavor)
+
+	client := &http.Client{Timeout: metadataCheckTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.Header.Get(metadataFlavorHeader) == metadataFlavor
+}
+
+// SetAccount method will define the service account (its email, or
+// default) to request tokens for, in the MetadataServer object
+func (m *MetadataServer) SetAccount(input string) {
+	if input == "" {
+		input = metadataDefaultAcct
+	}
+	m.Account = input
+	return
+}
+
+// SetScopes method will define the scopes to request, from the input
+// space-delimited list of scopes, in the MetadataServer object
+func (m *MetadataServer) SetScopes(input string) {
+	m.Scopes = strings.Fields(input)
+	return
+}
+
+// Token method will request an Access Token for the MetadataServer's
+// service account, storing it in its AccessToken
+func (m *MetadataServer) Token() error {
+	query := url.Values{}
+	if len(m.Scopes) > 0 {
+		query.Set("scopes", strings.Join(m.Scopes, ","))
+	}
+
+	body, err := m.get(`instance/service-accounts/`+m.Account+`/token`, query)
+	if err != nil {
+		return err
+	}
+
+	m.AccessToken = &AccessToken{}
+	if err := json.Unmarshal(body, m.AccessToken); err != nil {
+		return err
+	}
+
+	if len(m.Scopes) > 0 && m.AccessToken.Scopes == "" {
+		m.AccessToken.Scopes = strings.Join(m.Scopes, " ")
+	}
+	return nil
+}
+
+// IDToken method will request a Google-signed ID Token for the
+// MetadataServer's service account, for the input audience, storing
+// it in its AccessToken. The full format is requested, so that the
+// ID Token includes the service account's email
+func (m *MetadataServer) IDToken(audience string) error {
+	if audience == "" {
+		return errors.New(`Target audience not defined - mandatory field`)
+	}
+
+	body, err := m.get(`instance/service-accounts/`+m.Account+`/identity`, url.Values{
+		"audience": {audience},
+		"format":   {"full"},
+	})
+	if err != nil {
+		return err
+	}
+
+	token := strings.TrimSpace(string(body))
+	if token == "" {
+		return errors.New(`No ID Token found in the metadata server's response`)
+	}
+
+	m.AccessToken = &AccessToken{
+		IDToken: token,
+	}
+	return nil
+}
+
+// get method will request the input path (and query) from the
+// metadata server, with the mandatory Metadata-Flavor header
+func (m *MetadataServer) get(path string, query url.Values) ([]byte, error) {
+	endpoint := `http://` + m.Host + metadataPath + path
+	if len(query) > 0 {
+		endpoint += `?` + query.Encode()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(metadataFlavorHeader, metadataFlavor)
+
+	client := &http.Client{Timeout: metadataClientTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, errors.New(`Request to the metadata server (` + endpoint + `) failed: HTTP ` + strconv.Itoa(resp.StatusCode) + `
+
+` + string(body))
+	}
+
+	return body, nil
+}
@@ -0,0 +1,89 @@
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetadataServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(metadataFlavorHeader) != metadataFlavor {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set(metadataFlavorHeader, metadataFlavor)
+
+		switch r.URL.Path {
+		case metadataPath + "instance/service-accounts/default/token":
+			w.Write([]byte(`{"access_token":"MetadataToken-` + r.URL.Query().Get("scopes") + `","expires_in":3599,"token_type":"Bearer"}`))
+
+		case metadataPath + "instance/service-accounts/default/identity":
+			if r.URL.Query().Get("format") != "full" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`IDToken-` + r.URL.Query().Get("audience")))
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	setenv(t, MetadataHostEnv, strings.TrimPrefix(server.URL, "http://"))
+
+	tests := []struct {
+		account  string
+		scopes   string
+		audience string
+		want     string
+		ok       bool
+	}{
+		{
+			want: "MetadataToken-",
+			ok:   true,
+		}, {
+			scopes: "scope-a scope-b",
+			want:   "MetadataToken-scope-a,scope-b",
+			ok:     true,
+		}, {
+			audience: "https://service.run.app",
+			want:     "IDToken-https://service.run.app",
+			ok:       true,
+		}, {
+			account: "other@project.iam.gserviceaccount.com",
+			ok:      false,
+		},
+	}
+
+	for _, test := range tests {
+		m := NewMetadataServer()
+		if !m.OnGCE() {
+			t.Fatalf(`TestMetadataServer() = false, expected the metadata server to be available`)
+		}
+
+		m.SetAccount(test.account)
+		m.SetScopes(test.scopes)
+
+		var err error
+		var got string
+		if test.audience != "" {
+			err = m.IDToken(test.audience)
+			got = m.AccessToken.IDToken
+		} else {
+			err = m.Token()
+			got = m.AccessToken.Token
+		}
+
+		if (err == nil) != test.ok {
+			t.Errorf(`TestMetadataServer(%q, %q, %q) = %v, expected error to be %v`, test.account, test.scopes, test.audience, err, !test.ok)
+			continue
+		}
+
+		if test.ok && got != test.want {
+			t.Errorf(`TestMetadataServer(%q, %q, %q) = %q, expected result to be %q`, test.account, test.scopes, test.audience, got, test.want)
+		}
+	}
+}
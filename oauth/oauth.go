@@ -1,6 +1,8 @@
 package oauth
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 )
@@ -12,14 +14,33 @@ type TokenError struct {
 	Description string `json:"error_description"`
 }
 
+// ParseTokenError function will look into the input HTTP response
+// body, returning an error if it contains a TokenError, or nil
+// otherwise
+func ParseTokenError(body []byte) error {
+	chk := &TokenError{}
+
+	json.Unmarshal(body, chk)
+
+	if chk.Error != "" {
+		return errors.New(`Found error in response:
+
+	Error: ` + chk.Error + `
+	Desc: ` + chk.Description)
+	}
+	return nil
+}
+
 // AccessToken struct represents a JSON response containing an
 // Access Token, for either Client IDs or Service Accounts
 type AccessToken struct {
-	Token        string `json:"access_token,omitempty"`
-	Expiry       int    `json:"expires_in,omitempty"`
-	RefreshToken string `json:"refresh_token,omitempty"`
-	Scopes       string `json:"scope,omitempty"`
-	TokenType    string `json:"token_type,omitempty"`
+	Token           string `json:"access_token,omitempty"`
+	Expiry          int    `json:"expires_in,omitempty"`
+	RefreshToken    string `json:"refresh_token,omitempty"`
+	Scopes          string `json:"scope,omitempty"`
+	TokenType       string `json:"token_type,omitempty"`
+	IssuedTokenType string `json:"issued_token_type,omitempty"`
+	IDToken         string `json:"id_token,omitempty"`
 }
 
 // IsSet method will check whether the Access Token value is
@@ -32,21 +53,33 @@ func (a *AccessToken) IsSet() bool {
 	return false
 }
 
+// HasIDToken method will check whether the ID Token value is
+// set or not, returning a boolean (true / false) accordingly
+func (a *AccessToken) HasIDToken() bool {
+	return a.IDToken != ""
+}
+
 // PrintLong method will output a more verbose message when the
 // Access Token is about to be returned to the user
 func (a *AccessToken) PrintLong() {
-	if a.RefreshToken != "" {
-		fmt.Println(`====
+	output := `====
 Access Token: ` + a.Token + `
-Expiry: ` + strconv.Itoa(a.Expiry) + `
-Refresh Token: ` + a.RefreshToken + `
-====`)
-		return
+Expiry: ` + strconv.Itoa(a.Expiry)
+
+	if a.RefreshToken != "" {
+		output += `
+Refresh Token: ` + a.RefreshToken
+	}
+	if a.IDToken != "" {
+		output += `
+ID Token: ` + a.IDToken
+	}
+	if a.IssuedTokenType != "" {
+		output += `
+Issued Token Type: ` + a.IssuedTokenType
 	}
 
-	fmt.Println(`====
-Access Token: ` + a.Token + `
-Expiry: ` + strconv.Itoa(a.Expiry) + `
+	fmt.Println(output + `
 ====`)
 	return
 }
@@ -57,3 +90,22 @@ Expiry: ` + strconv.Itoa(a.Expiry) + `
 func (a *AccessToken) PrintShort() {
 	fmt.Print(a.Token)
 }
+
+// PrintShortIDToken method will output strictly the ID Token, without
+// line feeds, similar to PrintShort
+func (a *AccessToken) PrintShortIDToken() {
+	fmt.Print(a.IDToken)
+}
+
+// PrintLongIDToken method will output the ID Token followed by its
+// claims, which are decoded but not verified
+func (a *AccessToken) PrintLongIDToken() {
+	fmt.Println(`====
+ID Token: ` + a.IDToken + `
+====`)
+
+	if claims, err := DecodeIDToken(a.IDToken); err == nil {
+		claims.Print()
+	}
+	return
+}
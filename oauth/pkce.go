@@ -0,0 +1,85 @@
+package oauth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+const (
+	// PKCEMethodS256 is the SHA-256 code challenge method, which
+	// should be used whenever the provider supports it
+	PKCEMethodS256 string = `S256`
+
+	// PKCEMethodPlain is the plain code challenge method, where the
+	// challenge is the verifier itself
+	PKCEMethodPlain string = `plain`
+
+	pkceVerifierLength int = 64
+)
+
+// PKCE struct represents a Proof Key for Code Exchange (RFC 7636)
+// pair, used to bind an authorization request to its token request
+type PKCE struct {
+	Verifier  string
+	Challenge string
+	Method    string
+}
+
+// NewPKCE function will generate a new high-entropy code verifier
+// and its respective code challenge, for the input method
+func NewPKCE(method string) (*PKCE, error) {
+	if method != PKCEMethodS256 && method != PKCEMethodPlain {
+		return nil, errors.New(`Invalid PKCE method: ` + method + ` - must be either ` + PKCEMethodS256 + ` or ` + PKCEMethodPlain)
+	}
+
+	verifier, err := randomString(pkceVerifierLength)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &PKCE{
+		Method: method,
+	}
+	p.SetVerifier(verifier)
+	p.SetChallenge()
+
+	return p, nil
+}
+
+// SetVerifier method will define the code verifier for the PKCE
+// object
+func (p *PKCE) SetVerifier(input string) {
+	p.Verifier = input
+	return
+}
+
+// SetChallenge method will derive the code challenge from the
+// PKCE object's verifier, according to its method
+func (p *PKCE) SetChallenge() {
+	if p.Method == PKCEMethodPlain {
+		p.Challenge = p.Verifier
+		return
+	}
+
+	hash := sha256.Sum256([]byte(p.Verifier))
+	p.Challenge = base64.RawURLEncoding.EncodeToString(hash[:])
+	return
+}
+
+// GetVerifier method returns the code verifier from the PKCE object
+func (p *PKCE) GetVerifier() string {
+	return p.Verifier
+}
+
+// GetChallenge method returns the code challenge from the PKCE
+// object
+func (p *PKCE) GetChallenge() string {
+	return p.Challenge
+}
+
+// GetMethod method returns the code challenge method from the PKCE
+// object
+func (p *PKCE) GetMethod() string {
+	return p.Method
+}
@@ -0,0 +1,66 @@
+package oauth
+
+import "testing"
+
+func TestPKCEChallenge(t *testing.T) {
+	tests := []struct {
+		verifier string
+		method   string
+		want     string
+	}{
+		{
+			// RFC 7636, Appendix B
+			verifier: "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk",
+			method:   PKCEMethodS256,
+			want:     "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+		}, {
+			verifier: "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk",
+			method:   PKCEMethodPlain,
+			want:     "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk",
+		},
+	}
+
+	for _, test := range tests {
+		p := &PKCE{Method: test.method}
+		p.SetVerifier(test.verifier)
+		p.SetChallenge()
+
+		if p.GetChallenge() != test.want {
+			t.Errorf(`TestPKCEChallenge(%q, %q) = %q, expected result to be %q`, test.verifier, test.method, p.GetChallenge(), test.want)
+		}
+	}
+}
+
+func TestNewPKCE(t *testing.T) {
+	tests := []struct {
+		method string
+		ok     bool
+	}{
+		{
+			method: PKCEMethodS256,
+			ok:     true,
+		}, {
+			method: PKCEMethodPlain,
+			ok:     true,
+		}, {
+			method: "S512",
+			ok:     false,
+		},
+	}
+
+	for _, test := range tests {
+		p, err := NewPKCE(test.method)
+		if (err == nil) != test.ok {
+			t.Errorf(`TestNewPKCE(%q) = %v, expected error to be %v`, test.method, err, !test.ok)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		// RFC 7636, section 4.1: 43 to 128 characters
+		if len(p.GetVerifier()) < 43 || len(p.GetVerifier()) > 128 {
+			t.Errorf(`TestNewPKCE(%q) verifier length = %d, expected between 43 and 128`, test.method, len(p.GetVerifier()))
+		}
+	}
+}
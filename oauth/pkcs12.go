@@ -0,0 +1,102 @@
+package oauth
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/pkcs12"
+)
+
+// P12Password is the password of the PKCS#12 (.p12) keys generated
+// by Google Cloud
+const P12Password string = `notasecret`
+
+// ErrP12Password is returned when a PKCS#12 file can't be decrypted
+// with the provided password
+var ErrP12Password = errors.New(`Unable to decrypt the PKCS#12 file: incorrect password`)
+
+// IsPKCS12 function checks whether the input file is a PKCS#12 file,
+// based on its extension (.p12 or .pfx)
+func IsPKCS12(file string) bool {
+	ext := strings.ToLower(filepath.Ext(file))
+	return ext == ".p12" || ext == ".pfx"
+}
+
+// DecodePKCS12 function will decode the input PKCS#12 data with the
+// input password, returning its RSA private key. Only the legacy
+// format of Google's .p12 keys (pbeWithSHAAnd3-KeyTripleDES-CBC) is
+// supported; PBES2 (AES-encrypted) files, as exported by default with
+// OpenSSL 3, are not
+func DecodePKCS12(data []byte, password string) (*rsa.PrivateKey, error) {
+	key, _, err := pkcs12.Decode(data, password)
+	if err == pkcs12.ErrIncorrectPassword {
+		return nil, ErrP12Password
+	} else if _, ok := err.(pkcs12.NotImplementedError); ok {
+		return nil, errors.New(`Unsupported PKCS#12 file (` + err.Error() + `) - PBES2 / AES-encrypted .p12 files are not supported, only the legacy format of Google's .p12 keys: re-export it with 'openssl pkcs12 -legacy'`)
+	} else if err != nil {
+		return nil, errors.New(`Invalid PKCS#12 file: ` + err.Error())
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New(`Unsupported private key in the PKCS#12 file - only RSA keys are supported`)
+	}
+	return rsaKey, nil
+}
+
+// NewServiceAccountFromP12 function will create a ServiceAccount
+// object from the input PKCS#12 (.p12) key file, decrypted with the
+// input password, for the input service account email. The private
+// key ID is not part of .p12 files, so it is left empty
+func NewServiceAccountFromP12(file, password, email string) (*ServiceAccount, error) {
+	if email == "" {
+		return nil, errors.New(`Service account email not defined - mandatory field for .p12 keys`)
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := DecodePKCS12(data, password)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	svAcc := &ServiceAccount{
+		Type:        CredentialTypeServiceAccount,
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		ClientEmail: email,
+		TokenURI:    audienceURL,
+	}
+
+	// service account emails are formatted as
+	// {name}@{project}.iam.gserviceaccount.com
+	if parts := strings.SplitN(email, "@", 2); len(parts) == 2 && strings.HasSuffix(parts[1], ".iam.gserviceaccount.com") {
+		svAcc.ProjectID = strings.TrimSuffix(parts[1], ".iam.gserviceaccount.com")
+	}
+
+	return svAcc, nil
+}
+
+// WriteKeyfile method will write the ServiceAccount as a JSON keyfile
+// to the input path, readable only by the current user
+func (s *ServiceAccount) WriteKeyfile(file string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(file, append(data, '\n'))
+}
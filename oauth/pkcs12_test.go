@@ -0,0 +1,124 @@
+package oauth
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDecodePKCS12(t *testing.T) {
+	pemKey, err := ioutil.ReadFile(filepath.Join("testdata", "key.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := newKey(pemKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		file     string
+		password string
+		err      error
+		message  string
+		ok       bool
+	}{
+		{
+			// pbeWithSHAAnd3-KeyTripleDES-CBC key, as in Google's .p12 keys
+			file:     "legacy.p12",
+			password: P12Password,
+			ok:       true,
+		}, {
+			file:     "legacy.p12",
+			password: "wrong",
+			err:      ErrP12Password,
+			ok:       false,
+		}, {
+			// PBES2 (AES-256-CBC) key, as exported by default with OpenSSL 3
+			file:     "pbes2.p12",
+			password: P12Password,
+			message:  "PBES2 / AES-encrypted .p12 files are not supported",
+			ok:       false,
+		}, {
+			file:     "key.pem",
+			password: P12Password,
+			ok:       false,
+		},
+	}
+
+	for _, test := range tests {
+		data, err := ioutil.ReadFile(filepath.Join("testdata", test.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		key, err := DecodePKCS12(data, test.password)
+		if (err == nil) != test.ok {
+			t.Errorf(`TestDecodePKCS12(%q, %q) = %v, expected error to be %v`, test.file, test.password, err, !test.ok)
+			continue
+		}
+		if test.err != nil && err != test.err {
+			t.Errorf(`TestDecodePKCS12(%q, %q) = %v, expected error to be %v`, test.file, test.password, err, test.err)
+		}
+		if test.message != "" && !strings.Contains(err.Error(), test.message) {
+			t.Errorf(`TestDecodePKCS12(%q, %q) = %v, expected error to mention %q`, test.file, test.password, err, test.message)
+		}
+		if err != nil {
+			continue
+		}
+
+		if !key.Equal(want.PrivateKey) {
+			t.Errorf(`TestDecodePKCS12(%q, %q) returned an unexpected private key`, test.file, test.password)
+		}
+	}
+}
+
+func TestNewServiceAccountFromP12(t *testing.T) {
+	email := "account@project.iam.gserviceaccount.com"
+
+	svAcc, err := NewServiceAccountFromP12(filepath.Join("testdata", "legacy.p12"), P12Password, email)
+	if err != nil {
+		t.Fatalf(`TestNewServiceAccountFromP12() = %v, expected no error`, err)
+	}
+
+	if svAcc.ProjectID != "project" {
+		t.Errorf(`TestNewServiceAccountFromP12() = %q, expected project ID to be %q`, svAcc.ProjectID, "project")
+	}
+
+	// the converted keyfile must be a valid JSON keyfile, and signing
+	// must work through the usual JWT path
+	file := filepath.Join(t.TempDir(), "keyfile.json")
+	if err := svAcc.WriteKeyfile(file); err != nil {
+		t.Fatalf(`TestNewServiceAccountFromP12() = %v, expected keyfile to be written`, err)
+	}
+
+	if err := Validate(file).Err(); err != nil {
+		t.Errorf(`TestNewServiceAccountFromP12() = %v, expected a valid keyfile`, err)
+	}
+
+	svAcc.Init("https://www.googleapis.com/auth/cloud-platform", "")
+	if svAcc.JWT.GetOutput() == "" {
+		t.Errorf(`TestNewServiceAccountFromP12() = "", expected a signed JWT`)
+	}
+
+	// .p12 keys have no private key ID, which self-signed JWTs require
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf(`TestNewServiceAccountFromP12() = nil, expected an error for a self-signed JWT without a private key ID`)
+			}
+		}()
+		svAcc.InitSelfSigned("https://pubsub.googleapis.com/", "")
+	}()
+
+	svAcc.PrivateKeyID = "KeyID"
+	svAcc.InitSelfSigned("https://pubsub.googleapis.com/", "")
+	if !svAcc.AccessToken.IsSet() {
+		t.Errorf(`TestNewServiceAccountFromP12() = "", expected a self-signed JWT with a private key ID`)
+	}
+
+	if _, err := NewServiceAccountFromP12(filepath.Join("testdata", "legacy.p12"), P12Password, ""); err == nil {
+		t.Errorf(`TestNewServiceAccountFromP12() = nil, expected an error without a service account email`)
+	}
+}
@@ -0,0 +1,155 @@
+package oauth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// AuthMethodClientSecretPost sends the client credentials in the
+	// request body (default)
+	AuthMethodClientSecretPost string = `client_secret_post`
+
+	// AuthMethodClientSecretBasic sends the client credentials in the
+	// Authorization header, with HTTP Basic authentication
+	AuthMethodClientSecretBasic string = `client_secret_basic`
+
+	// AuthMethodNone sends only the Client ID, for public clients
+	AuthMethodNone string = `none`
+)
+
+// postForm function will POST the input form to the input endpoint as
+// application/x-www-form-urlencoded (RFC 6749, section 4), using HTTP
+// Basic authentication if a username is set. Error responses are
+// returned as-is if they contain a TokenError, so that the caller can
+// handle them
+func postForm(endpoint string, form url.Values, username, password string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if username != "" {
+		// RFC 6749, section 2.3.1: credentials are form-encoded
+		// before being used in the Basic authentication scheme
+		req.SetBasicAuth(url.QueryEscape(username), url.QueryEscape(password))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusMultipleChoices && ParseTokenError(body) == nil {
+		return nil, errors.New(`Request to ` + endpoint + ` failed: HTTP ` + strconv.Itoa(resp.StatusCode) + `
+
+` + string(body))
+	}
+
+	return body, nil
+}
+
+// PostForm method will POST the input form to the input endpoint,
+// authenticating the ClientID according to its authentication method
+func (c *ClientID) PostForm(endpoint string, form url.Values) ([]byte, error) {
+	switch c.AuthMethod {
+	case AuthMethodClientSecretPost, "":
+		form.Set("client_id", c.GetID())
+		form.Set("client_secret", c.GetSecret())
+		return postForm(endpoint, form, "", "")
+
+	case AuthMethodClientSecretBasic:
+		return postForm(endpoint, form, c.GetID(), c.GetSecret())
+
+	case AuthMethodNone:
+		form.Set("client_id", c.GetID())
+		return postForm(endpoint, form, "", "")
+
+	case AuthMethodPrivateKeyJWT:
+		// the assertion's audience is the token endpoint, which is
+		// also accepted on other endpoints of the same server
+		assertion, err := c.NewClientAssertion(c.RefreshToken.GetTokenURL())
+		if err != nil {
+			return nil, err
+		}
+
+		form.Set("client_id", c.GetID())
+		form.Set("client_assertion_type", clientAssertionType)
+		form.Set("client_assertion", assertion)
+		return postForm(endpoint, form, "", "")
+
+	default:
+		return nil, errors.New(`Invalid client authentication method: ` + c.AuthMethod + ` - must be one of ` +
+			AuthMethodClientSecretPost + `, ` + AuthMethodClientSecretBasic + `, ` + AuthMethodPrivateKeyJWT + ` or ` + AuthMethodNone)
+	}
+}
+
+// apiError represents a JSON error response from a Google API
+// (as opposed to an OAuth 2.0 TokenError)
+type apiError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+// postJSON function will POST the input object, encoded as JSON, to
+// the input endpoint, authenticated with the input bearer token.
+// Non-2xx responses are returned as errors, including the API's
+// error message if present
+func postJSON(endpoint, token string, input interface{}) ([]byte, error) {
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		chk := &apiError{}
+		if json.Unmarshal(body, chk) == nil && chk.Error.Message != "" {
+			return nil, errors.New(`Found error in response:
+
+	Error: ` + chk.Error.Status + `
+	Desc: ` + chk.Error.Message)
+		}
+
+		return nil, errors.New(`Request to ` + endpoint + ` failed: HTTP ` + strconv.Itoa(resp.StatusCode) + `
+
+` + string(body))
+	}
+
+	return body, nil
+}
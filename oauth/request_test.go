@@ -0,0 +1,71 @@
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestClientIDPostForm(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		r.ParseForm()
+
+		id, secret, basic := r.BasicAuth()
+		if !basic {
+			id = r.PostForm.Get("client_id")
+			secret = r.PostForm.Get("client_secret")
+		}
+
+		// the Client ID has a reserved character, which must be
+		// form-encoded in the Basic authentication header
+		if id != "Client%3AID" && id != "Client:ID" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		w.Write([]byte(secret))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		method string
+		want   string
+		ok     bool
+	}{
+		{
+			method: AuthMethodClientSecretPost,
+			want:   "ClientSecret",
+			ok:     true,
+		}, {
+			method: AuthMethodClientSecretBasic,
+			want:   "ClientSecret",
+			ok:     true,
+		}, {
+			method: AuthMethodNone,
+			want:   "",
+			ok:     true,
+		}, {
+			method: "client_secret_jwt",
+			ok:     false,
+		},
+	}
+
+	for _, test := range tests {
+		clientID, _ := NewClientID("Client:ID", "ClientSecret", "openid", "")
+		clientID.SetAuthMethod(test.method)
+
+		body, err := clientID.PostForm(server.URL, url.Values{"grant_type": {"refresh_token"}})
+		if (err == nil) != test.ok {
+			t.Errorf(`TestClientIDPostForm(%q) = %v, expected error to be %v`, test.method, err, !test.ok)
+			continue
+		}
+		if err == nil && string(body) != test.want {
+			t.Errorf(`TestClientIDPostForm(%q) = %q, expected result to be %q`, test.method, string(body), test.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package oauth
+
+import (
+	"errors"
+	"net/url"
+)
+
+// ErrNoToken is returned when there is no token to revoke
+var ErrNoToken = errors.New(`No token to revoke - an Access Token or Refresh Token must be set`)
+
+// Revoke function will revoke the input token (either an Access
+// Token or a Refresh Token) at the input revocation endpoint, as
+// described in RFC 7009
+func Revoke(endpoint, token string) error {
+	if token == "" {
+		return ErrNoToken
+	}
+
+	body, err := postForm(endpoint, url.Values{
+		"token": {token},
+	}, "", "")
+	if err != nil {
+		return err
+	}
+
+	return ParseTokenError(body)
+}
+
+// Revoke method will revoke the AccessToken's token at the input
+// revocation endpoint. If all is set to true, its Refresh Token
+// (if present) is also revoked
+func (a *AccessToken) Revoke(endpoint string, all bool) error {
+	if !a.IsSet() && (!all || a.RefreshToken == "") {
+		return ErrNoToken
+	}
+
+	if a.IsSet() {
+		if err := Revoke(endpoint, a.Token); err != nil {
+			return err
+		}
+	}
+
+	if all && a.RefreshToken != "" {
+		if err := Revoke(endpoint, a.RefreshToken); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Revoke method will revoke the ClientID's Refresh Token. If all is
+// set to true, its Access Token (if present) is also revoked
+func (c *ClientID) Revoke(all bool) error {
+	hasAccessToken := all && c.AccessToken.IsSet()
+
+	if !c.RefreshToken.HasToken() && !hasAccessToken {
+		return ErrNoToken
+	}
+
+	if hasAccessToken {
+		if err := c.revoke(c.AccessToken.Token); err != nil {
+			return err
+		}
+	}
+
+	if c.RefreshToken.HasToken() {
+		if err := c.revoke(c.RefreshToken.GetToken()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// revoke method will revoke the input token at the ClientID's
+// revocation endpoint, authenticating the client as required by
+// RFC 7009 for confidential clients
+func (c *ClientID) revoke(token string) error {
+	body, err := c.PostForm(c.RefreshToken.GetRevokeURL(), url.Values{
+		"token": {token},
+	})
+	if err != nil {
+		return err
+	}
+
+	return ParseTokenError(body)
+}
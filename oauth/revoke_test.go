@@ -0,0 +1,125 @@
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRevoke(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("token") != "ValidToken" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_token","error_description":"Token expired or revoked"}`))
+			return
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		token *AccessToken
+		all   bool
+		ok    bool
+	}{
+		{
+			token: &AccessToken{Token: "ValidToken"},
+			all:   false,
+			ok:    true,
+		}, {
+			token: &AccessToken{Token: "ValidToken", RefreshToken: "ValidToken"},
+			all:   true,
+			ok:    true,
+		}, {
+			token: &AccessToken{Token: "ValidToken", RefreshToken: "RevokedToken"},
+			all:   true,
+			ok:    false,
+		}, {
+			token: &AccessToken{RefreshToken: "ValidToken"},
+			all:   false,
+			ok:    false,
+		}, {
+			token: &AccessToken{},
+			all:   true,
+			ok:    false,
+		},
+	}
+
+	for _, test := range tests {
+		err := test.token.Revoke(server.URL, test.all)
+		if (err == nil) != test.ok {
+			t.Errorf(`TestRevoke(%v, %v) = %v, expected error to be %v`, test.token, test.all, err, !test.ok)
+		}
+	}
+}
+
+func TestClientIDRevoke(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+
+		// public clients only identify themselves, while confidential
+		// clients must authenticate (RFC 7009, section 2.1)
+		if r.PostForm.Get("client_id") != "ClientID" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		if secret, ok := r.PostForm["client_secret"]; ok && secret[0] != "ClientSecret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		if r.PostForm.Get("token") != "ValidToken" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_token","error_description":"Token expired or revoked"}`))
+			return
+		}
+	}))
+	defer server.Close()
+
+	public, _ := NewPublicClientID("ClientID", "")
+	confidential, _ := NewClientCredentials("ClientID", "ClientSecret", "")
+	wrongSecret, _ := NewClientCredentials("ClientID", "OtherSecret", "")
+
+	tests := []struct {
+		name   string
+		client *ClientID
+		token  string
+		ok     bool
+	}{
+		{
+			name:   "public client",
+			client: public,
+			token:  "ValidToken",
+			ok:     true,
+		}, {
+			name:   "confidential client",
+			client: confidential,
+			token:  "ValidToken",
+			ok:     true,
+		}, {
+			name:   "invalid client secret",
+			client: wrongSecret,
+			token:  "ValidToken",
+			ok:     false,
+		}, {
+			name:   "revoked token",
+			client: public,
+			token:  "RevokedToken",
+			ok:     false,
+		},
+	}
+
+	for _, test := range tests {
+		test.client.SetEndpoint(&Endpoint{RevokeURL: server.URL})
+		test.client.RefreshToken.SetToken(test.token)
+
+		err := test.client.Revoke(false)
+		if (err == nil) != test.ok {
+			t.Errorf(`TestClientIDRevoke(%s) = %v, expected error to be %v`, test.name, err, !test.ok)
+		}
+	}
+
+	if _, err := NewPublicClientID("", ""); err == nil {
+		t.Errorf(`TestClientIDRevoke() = nil, expected an error for a public client without a Client ID`)
+	}
+}
@@ -1,33 +1,67 @@
 package oauth
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
-	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	selfSignedLifetime time.Duration = time.Hour
 )
 
 // ServiceAccount struct represents a service account object
 // based on the provided JSON key file from GCP
 type ServiceAccount struct {
-	Type            string `json:"type,omitempty"`
-	ProjectID       string `json:"project_id,omitempty"`
-	PrivateKeyID    string `json:"private_key_id,omitempty"`
-	PrivateKey      string `json:"private_key,omitempty"`
-	ClientEmail     string `json:"client_email,omitempty"`
-	ClientID        string `json:"client_id,omitempty"`
-	AuthURI         string `json:"auth_uri,omitempty"`
-	TokenURI        string `json:"token_uri,omitempty"`
-	AuthProvCertURL string `json:"auth_provider_x509_cert_url,omitempty"`
-	ClientCertURL   string `json:"client_x509_cert_url,omitempty"`
-	JWT             *JWT
-	AccessToken     *AccessToken
+	Type            string       `json:"type,omitempty"`
+	ProjectID       string       `json:"project_id,omitempty"`
+	PrivateKeyID    string       `json:"private_key_id,omitempty"`
+	PrivateKey      string       `json:"private_key,omitempty"`
+	ClientEmail     string       `json:"client_email,omitempty"`
+	ClientID        string       `json:"client_id,omitempty"`
+	AuthURI         string       `json:"auth_uri,omitempty"`
+	TokenURI        string       `json:"token_uri,omitempty"`
+	AuthProvCertURL string       `json:"auth_provider_x509_cert_url,omitempty"`
+	ClientCertURL   string       `json:"client_x509_cert_url,omitempty"`
+	JWT             *JWT         `json:"-"`
+	AccessToken     *AccessToken `json:"-"`
 }
 
 // NewServiceAccount function creates a new ServiceAccount object
 // based on the input parameters
 func NewServiceAccount(file, scope, sub string) *ServiceAccount {
+	svAcc := readServiceAccount(file)
+
+	svAcc.Init(scope, sub)
+	return svAcc
+}
+
+// NewServiceAccountIDToken function creates a new ServiceAccount
+// object requesting a Google-signed ID Token for the input target
+// audience (like a Cloud Run service URL), instead of an Access Token
+func NewServiceAccountIDToken(file, audience string) *ServiceAccount {
+	svAcc := readServiceAccount(file)
+
+	svAcc.InitIDToken(audience)
+	return svAcc
+}
+
+// NewServiceAccountSelfSigned function creates a new ServiceAccount
+// object whose AccessToken is a JWT signed by the service account's
+// key, for the input audience (the API's service URL, like
+// https://pubsub.googleapis.com/) and / or scopes. This token is
+// accepted by Google APIs directly, without a request to the token
+// endpoint
+func NewServiceAccountSelfSigned(file, audience, scope string) *ServiceAccount {
+	svAcc := readServiceAccount(file)
+
+	svAcc.InitSelfSigned(audience, scope)
+	return svAcc
+}
+
+func readServiceAccount(file string) *ServiceAccount {
 	svAcc := &ServiceAccount{}
 
 	f, err := ioutil.ReadFile(file)
@@ -39,13 +73,87 @@ func NewServiceAccount(file, scope, sub string) *ServiceAccount {
 		panic(err)
 	}
 
-	svAcc.Init(scope, sub)
+	if svAcc.Type != "" && svAcc.Type != CredentialTypeServiceAccount {
+		panic(errors.New(`Invalid credential type in ` + file + `: ` + svAcc.Type + ` - expected ` + CredentialTypeServiceAccount))
+	}
+
+	// only the problems which would fail every request are checked;
+	// the validation flag reports the remaining ones
+	if _, err := newKey([]byte(svAcc.PrivateKey)); err != nil {
+		panic(errors.New(`Unable to parse the private key in ` + file + `: ` + err.Error()))
+	}
+	if svAcc.ClientEmail == "" {
+		panic(errors.New(`Client email not defined in ` + file + ` - mandatory field`))
+	}
+	if svAcc.TokenURI == "" {
+		panic(errors.New(`Token URI not defined in ` + file + ` - mandatory field`))
+	}
+
 	return svAcc
 }
 
 // Init method will initiate a ServiceAccount object by
 // creating (and signing) the JWT for the request
 func (s *ServiceAccount) Init(scope, sub string) {
+	s.initJWT()
+
+	s.JWT.Claim.SetScope(scope)
+
+	if sub != "" {
+		s.JWT.Claim.SetSubscriber(sub)
+	}
+
+	s.signJWT()
+}
+
+// InitIDToken method will initiate a ServiceAccount object by
+// creating (and signing) the JWT for an ID Token request, for the
+// input target audience
+func (s *ServiceAccount) InitIDToken(audience string) {
+	s.initJWT()
+
+	s.JWT.Claim.SetTargetAudience(audience)
+
+	s.signJWT()
+}
+
+// InitSelfSigned method will initiate a ServiceAccount object by
+// creating (and signing) a JWT to be used directly as a bearer token,
+// for the input audience and / or scopes. The JWT's header references
+// the private key ID, so that Google can verify its signature
+func (s *ServiceAccount) InitSelfSigned(audience, scope string) {
+	if audience == "" && scope == "" {
+		panic(errors.New(`Self-signed JWTs require either an audience or scopes`))
+	}
+	if s.PrivateKeyID == "" {
+		panic(errors.New(`Private key ID not defined - mandatory field for self-signed JWTs`))
+	}
+
+	s.JWT = &JWT{
+		Claim: &JWTClaim{},
+	}
+
+	if err := s.JWT.SetKeyID(s.PrivateKeyID); err != nil {
+		panic(err)
+	}
+
+	s.JWT.Claim.SetIssuer(s.GetEmail())
+	s.JWT.Claim.SetSubscriber(s.GetEmail())
+	s.JWT.Claim.SetAudience(audience)
+	s.JWT.Claim.SetScope(scope)
+	s.JWT.Claim.SetLifetime(selfSignedLifetime)
+
+	s.signJWT()
+
+	s.AccessToken = &AccessToken{
+		Token:     s.JWT.GetOutput(),
+		Expiry:    int(selfSignedLifetime / time.Second),
+		Scopes:    scope,
+		TokenType: "Bearer",
+	}
+}
+
+func (s *ServiceAccount) initJWT() {
 	s.JWT = &JWT{
 		Claim: &JWTClaim{},
 	}
@@ -54,14 +162,11 @@ func (s *ServiceAccount) Init(scope, sub string) {
 	s.JWT.InitHeader()
 
 	s.JWT.Claim.SetIssuer(s.GetEmail())
-	s.JWT.Claim.SetScope(scope)
 	s.JWT.Claim.SetAudience(s.GetTokenURI())
 	s.JWT.Claim.SetExpiry()
+}
 
-	if sub != "" {
-		s.JWT.Claim.SetSubscriber(sub)
-	}
-
+func (s *ServiceAccount) signJWT() {
 	var err error
 	if s.JWT.Signature, err = s.JWT.Sign(s.PrivateKey); err != nil {
 		panic(err)
@@ -76,26 +181,10 @@ func (s *ServiceAccount) Init(scope, sub string) {
 // Auth method will issue a request for an Access Token, based
 // on the created JWT
 func (s *ServiceAccount) Auth() {
-	post, err := json.Marshal(map[string]string{
-		"grant_type": `urn:ietf:params:oauth:grant-type:jwt-bearer`,
-		"assertion":  s.JWT.GetOutput(),
-	})
-
-	if err != nil {
-		panic(err)
-	}
-
-	postBytes := bytes.NewBuffer(post)
-
-	resp, err := http.Post(s.GetTokenURI(), `application/json`, postBytes)
-
-	if err != nil {
-		panic(err)
-	}
-
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
+	body, err := postForm(s.GetTokenURI(), url.Values{
+		"grant_type": {`urn:ietf:params:oauth:grant-type:jwt-bearer`},
+		"assertion":  {s.JWT.GetOutput()},
+	}, "", "")
 
 	if err != nil {
 		panic(err)
@@ -145,15 +234,8 @@ func (s *ServiceAccount) GetTokenURI() string {
 // CheckResponse function will look into the returned HTTP response
 // to check whether it actually contains an error
 func CheckResponse(body []byte) {
-	chk := &TokenError{}
-
-	json.Unmarshal(body, chk)
-
-	if chk.Error != "" {
-		panic(errors.New(`Found error in response:
-
-	Error: ` + chk.Error + `
-	Desc: ` + chk.Description))
+	if err := ParseTokenError(body); err != nil {
+		panic(err)
 	}
 
 }
@@ -0,0 +1,111 @@
+package oauth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+const (
+	stateLength int = 32
+	nonceLength int = 32
+)
+
+// StateError is returned when the state value returned by the
+// authorization server doesn't match the one sent in the request
+type StateError struct {
+	Want string
+	Got  string
+}
+
+// Error method implements the error interface
+func (e *StateError) Error() string {
+	if e.Got == "" {
+		return `State mismatch: no state returned in the authorization redirect`
+	}
+	return `State mismatch: expected ` + e.Want + ` in the authorization redirect, got ` + e.Got
+}
+
+// CheckState function will compare the expected state with the one
+// returned by the authorization server, returning a *StateError if
+// they don't match
+func CheckState(want, got string) error {
+	if want != got {
+		return &StateError{
+			Want: want,
+			Got:  got,
+		}
+	}
+	return nil
+}
+
+// ParseRedirect function will extract the Access Code from the input,
+// which can either be the code itself or the full redirect URL
+// returned by the authorization server. When a redirect URL is
+// provided, its state is verified against the input state
+func ParseRedirect(input, state string) (string, error) {
+	input = strings.TrimSpace(input)
+
+	if !isRedirect(input) {
+		return input, nil
+	}
+
+	var query url.Values
+	if u, err := url.Parse(input); err == nil && u.RawQuery != "" {
+		query = u.Query()
+	} else {
+		query, err = url.ParseQuery(strings.TrimPrefix(input, "?"))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if err := redirectError(query); err != nil {
+		return "", err
+	}
+
+	if state != "" {
+		if err := CheckState(state, query.Get("state")); err != nil {
+			return "", err
+		}
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		return "", errors.New(`No authorization code in the redirect URL`)
+	}
+	return code, nil
+}
+
+// isRedirect function checks whether the input is a redirect URL (or
+// its query), rather than the Access Code itself
+func isRedirect(input string) bool {
+	if strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://") {
+		return true
+	}
+	for _, param := range []string{"code=", "error=", "state="} {
+		if strings.Contains(input, param) {
+			return true
+		}
+	}
+	return false
+}
+
+func randomString(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hasScope(scopes, scope string) bool {
+	for _, s := range strings.Fields(scopes) {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package oauth
+
+import "testing"
+
+func TestParseRedirect(t *testing.T) {
+	tests := []struct {
+		input string
+		state string
+		want  string
+		ok    bool
+	}{
+		{
+			input: "4/SomeAccessCode",
+			state: "SomeState",
+			want:  "4/SomeAccessCode",
+			ok:    true,
+		}, {
+			input: "http://127.0.0.1:8080/?state=SomeState&code=4/SomeAccessCode&scope=email",
+			state: "SomeState",
+			want:  "4/SomeAccessCode",
+			ok:    true,
+		}, {
+			input: "http://127.0.0.1:8080/?state=OtherState&code=4/SomeAccessCode&scope=email",
+			state: "SomeState",
+			want:  "",
+			ok:    false,
+		}, {
+			input: "http://127.0.0.1:8080/?code=4/SomeAccessCode&scope=email",
+			state: "SomeState",
+			want:  "",
+			ok:    false,
+		}, {
+			input: "http://127.0.0.1:8080/?state=SomeState&scope=email",
+			state: "SomeState",
+			want:  "",
+			ok:    false,
+		}, {
+			input: "?state=SomeState",
+			want:  "",
+			ok:    false,
+		}, {
+			input: "http://127.0.0.1:8080/?error=access_denied&state=SomeState",
+			state: "SomeState",
+			want:  "",
+			ok:    false,
+		},
+	}
+
+	for _, test := range tests {
+		code, err := ParseRedirect(test.input, test.state)
+		if (err == nil) != test.ok {
+			t.Errorf(`TestParseRedirect(%q) = %v, expected error to be %v`, test.input, err, !test.ok)
+		}
+		if code != test.want {
+			t.Errorf(`TestParseRedirect(%q) = %q, expected result to be %q`, test.input, code, test.want)
+		}
+	}
+}
+
+func TestInitStateNonce(t *testing.T) {
+	tests := []struct {
+		scopes string
+		nonce  bool
+	}{
+		{
+			scopes: "https://www.googleapis.com/auth/userinfo.email",
+			nonce:  false,
+		}, {
+			scopes: "openid https://www.googleapis.com/auth/userinfo.email",
+			nonce:  true,
+		},
+	}
+
+	for _, test := range tests {
+		clientID, _ := NewClientID("ClientID", "ClientSecret", test.scopes, "")
+
+		if err := clientID.InitState(); err != nil {
+			t.Errorf(`TestInitStateNonce(%q) = %v, expected no error`, test.scopes, err)
+			continue
+		}
+		if clientID.GetState() == "" {
+			t.Errorf(`TestInitStateNonce(%q) expected a state to be set`, test.scopes)
+		}
+		if (clientID.GetNonce() != "") != test.nonce {
+			t.Errorf(`TestInitStateNonce(%q) = %q, expected nonce to be set: %v`, test.scopes, clientID.GetNonce(), test.nonce)
+		}
+	}
+}
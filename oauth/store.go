@@ -0,0 +1,66 @@
+package oauth
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TokenStore interface describes a place where a Refresh Token is
+// loaded from, and where it is written back to when it is rotated
+type TokenStore interface {
+	Load() (string, error)
+	Save(token string) error
+}
+
+// FileStore struct represents a plain-text file holding a single
+// Refresh Token
+type FileStore struct {
+	Path string
+}
+
+// NewFileStore function will create a FileStore for the input path
+func NewFileStore(path string) *FileStore {
+	return &FileStore{
+		Path: path,
+	}
+}
+
+// Load method returns the Refresh Token stored in the FileStore's
+// file
+func (f *FileStore) Load() (string, error) {
+	buf, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(buf)), nil
+}
+
+// Save method will (atomically) replace the FileStore's file contents
+// with the input Refresh Token, readable only by the current user
+func (f *FileStore) Save(token string) error {
+	return writeFileAtomic(f.Path, []byte(token+"\n"))
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), `.`+filepath.Base(path)+`.*`)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
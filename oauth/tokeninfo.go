@@ -0,0 +1,234 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// GoogleTokenInfoURL is Google's tokeninfo endpoint
+	GoogleTokenInfoURL string = `https://oauth2.googleapis.com/tokeninfo`
+
+	// TokenTypeAccessToken is the tokeninfo parameter for Access Tokens
+	TokenTypeAccessToken string = `access_token`
+
+	// TokenTypeIDToken is the tokeninfo parameter for ID Tokens
+	TokenTypeIDToken string = `id_token`
+)
+
+// ErrInvalidToken is returned when an inspected token is not active
+// or already expired
+var ErrInvalidToken = errors.New(`Token is invalid or expired`)
+
+// Audience type represents a token's audience, which can either be
+// a single string or a list of strings
+type Audience []string
+
+// UnmarshalJSON method implements the json.Unmarshaler interface,
+// accepting both a string and a list of strings
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = Audience(list)
+	return nil
+}
+
+// String method returns the audience values as a comma-separated
+// string
+func (a Audience) String() string {
+	return strings.Join(a, ", ")
+}
+
+// flexString is a string which can also be unmarshalled from JSON
+// numbers and booleans, since tokeninfo returns every value as a
+// string while RFC 7662 introspection returns them as-is
+type flexString string
+
+// UnmarshalJSON method implements the json.Unmarshaler interface
+func (f *flexString) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*f = flexString(s)
+		return nil
+	}
+	*f = flexString(strings.TrimSpace(string(data)))
+	return nil
+}
+
+// TokenInfo struct represents a JSON response from either Google's
+// tokeninfo endpoint or an RFC 7662 introspection endpoint
+type TokenInfo struct {
+	Active          *bool      `json:"active,omitempty"`
+	Issuer          string     `json:"iss,omitempty"`
+	AuthorizedParty string     `json:"azp,omitempty"`
+	Audience        Audience   `json:"aud,omitempty"`
+	Subject         string     `json:"sub,omitempty"`
+	ClientID        string     `json:"client_id,omitempty"`
+	Username        string     `json:"username,omitempty"`
+	Email           string     `json:"email,omitempty"`
+	EmailVerified   flexString `json:"email_verified,omitempty"`
+	HostedDomain    string     `json:"hd,omitempty"`
+	Scope           string     `json:"scope,omitempty"`
+	TokenType       string     `json:"token_type,omitempty"`
+	AccessType      string     `json:"access_type,omitempty"`
+	Expiry          flexString `json:"exp,omitempty"`
+	ExpiresIn       flexString `json:"expires_in,omitempty"`
+	IssuedAt        flexString `json:"iat,omitempty"`
+}
+
+// Inspect function will query Google's tokeninfo endpoint (or one
+// with the same API) for the input token. The tokenType should be
+// either TokenTypeAccessToken or TokenTypeIDToken
+func Inspect(endpoint, token, tokenType string) (*TokenInfo, error) {
+	if token == "" {
+		return nil, errors.New(`No token to inspect`)
+	}
+
+	resp, err := http.Get(endpoint + `?` + url.Values{tokenType: {token}}.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK && ParseTokenError(body) == nil {
+		return nil, errors.New(`Unable to inspect token: HTTP ` + strconv.Itoa(resp.StatusCode) + `
+
+` + string(body))
+	}
+
+	return parseTokenInfo(body)
+}
+
+// Introspect function will query an RFC 7662 introspection endpoint
+// for the input token, authenticating with the input Client ID and
+// secret (if set)
+func Introspect(endpoint, token, clientID, secret string) (*TokenInfo, error) {
+	if token == "" {
+		return nil, errors.New(`No token to inspect`)
+	}
+
+	body, err := postForm(endpoint, url.Values{
+		"token": {token},
+	}, clientID, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTokenInfo(body)
+}
+
+func parseTokenInfo(body []byte) (*TokenInfo, error) {
+	if err := ParseTokenError(body); err != nil {
+		return nil, err
+	}
+
+	info := &TokenInfo{}
+	if err := json.Unmarshal(body, info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// Inspect method will query the input tokeninfo endpoint for the
+// AccessToken's token
+func (a *AccessToken) Inspect(endpoint string) (*TokenInfo, error) {
+	return Inspect(endpoint, a.Token, TokenTypeAccessToken)
+}
+
+// GetExpiry method returns the token's absolute expiry time, or a
+// zero time if it isn't known
+func (t *TokenInfo) GetExpiry() time.Time {
+	exp, err := strconv.ParseInt(string(t.Expiry), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(exp, 0)
+}
+
+// GetScopes method returns the token's scopes as a list
+func (t *TokenInfo) GetScopes() []string {
+	return strings.Fields(t.Scope)
+}
+
+// Validate method will check whether the token is active and not
+// expired, and whether it was granted all the input (space-delimited)
+// scopes, returning an error describing the first problem found
+func (t *TokenInfo) Validate(scopes string) error {
+	if t.Active != nil && !*t.Active {
+		return ErrInvalidToken
+	}
+
+	if exp := t.GetExpiry(); !exp.IsZero() && exp.Before(time.Now()) {
+		return ErrInvalidToken
+	}
+
+	var missing []string
+	for _, scope := range strings.Fields(scopes) {
+		if !hasScope(t.Scope, scope) {
+			missing = append(missing, scope)
+		}
+	}
+
+	if len(missing) > 0 {
+		return errors.New(`Token is missing required scopes: ` + strings.Join(missing, " "))
+	}
+
+	return nil
+}
+
+// Print method will output the TokenInfo's set values
+func (t *TokenInfo) Print() {
+	var b strings.Builder
+	line := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%s: %s\n", key, value)
+		}
+	}
+
+	b.WriteString("====\n")
+	if t.Active != nil {
+		line("Active", strconv.FormatBool(*t.Active))
+	}
+	line("Issuer", t.Issuer)
+	line("Authorized Party", t.AuthorizedParty)
+	line("Audience", t.Audience.String())
+	line("Subject", t.Subject)
+	line("Client ID", t.ClientID)
+	line("Username", t.Username)
+	line("Email", t.Email)
+	line("Email Verified", string(t.EmailVerified))
+	line("Hosted Domain", t.HostedDomain)
+	line("Token Type", t.TokenType)
+	line("Access Type", t.AccessType)
+	if scopes := t.GetScopes(); len(scopes) > 0 {
+		line("Scopes", strings.Join(scopes, "\n\t"))
+	}
+	if exp := t.GetExpiry(); !exp.IsZero() {
+		line("Expiry", exp.Format(time.RFC3339)+` (in `+time.Until(exp).Round(time.Second).String()+`)`)
+	}
+	b.WriteString("====")
+
+	fmt.Println(b.String())
+}
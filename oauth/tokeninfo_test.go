@@ -0,0 +1,56 @@
+package oauth
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTokenInfoValidate(t *testing.T) {
+	future := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	past := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	tests := []struct {
+		body   string
+		scopes string
+		ok     bool
+	}{
+		{
+			// tokeninfo response, values as strings
+			body:   `{"azp":"ClientID","aud":"ClientID","scope":"https://www.googleapis.com/auth/userinfo.email openid","exp":"` + future + `","expires_in":"3599","email_verified":"true"}`,
+			scopes: "openid",
+			ok:     true,
+		}, {
+			body:   `{"azp":"ClientID","aud":"ClientID","scope":"https://www.googleapis.com/auth/userinfo.email","exp":"` + future + `"}`,
+			scopes: "openid",
+			ok:     false,
+		}, {
+			body:   `{"azp":"ClientID","aud":"ClientID","scope":"openid","exp":"` + past + `"}`,
+			scopes: "",
+			ok:     false,
+		}, {
+			// RFC 7662 response, values as-is
+			body:   `{"active":true,"client_id":"ClientID","aud":["api","other"],"scope":"read write","exp":` + future + `}`,
+			scopes: "read write",
+			ok:     true,
+		}, {
+			body:   `{"active":false}`,
+			scopes: "",
+			ok:     false,
+		},
+	}
+
+	for _, test := range tests {
+		info := &TokenInfo{}
+		if err := json.Unmarshal([]byte(test.body), info); err != nil {
+			t.Errorf(`TestTokenInfoValidate(%q) failed to unmarshal: %v`, test.body, err)
+			continue
+		}
+
+		err := info.Validate(test.scopes)
+		if (err == nil) != test.ok {
+			t.Errorf(`TestTokenInfoValidate(%q, %q) = %v, expected error to be %v`, test.body, test.scopes, err, !test.ok)
+		}
+	}
+}
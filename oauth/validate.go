@@ -0,0 +1,319 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// SeverityError marks a problem which prevents the credentials
+	// from being used
+	SeverityError string = `error`
+
+	// SeverityWarning marks a problem which may prevent some features
+	// from working, or hints at a mistake
+	SeverityWarning string = `warning`
+
+	// CredentialTypeClientSecret is the type reported for
+	// client_secret.json files, which have no type field
+	CredentialTypeClientSecret string = `client_secret`
+
+	minRSAKeyBits int = 2048
+)
+
+// Problem struct represents an issue found when validating a
+// credentials file, on one of its fields
+type Problem struct {
+	Field    string `json:"field,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// ValidationReport struct represents the result of validating a
+// credentials file, listing every problem found in it. The file is
+// valid if no problem has an error severity
+type ValidationReport struct {
+	File     string     `json:"file"`
+	Type     string     `json:"type,omitempty"`
+	Valid    bool       `json:"valid"`
+	Problems []*Problem `json:"problems"`
+}
+
+// Validate function will read the input credentials file (a service
+// account keyfile, an external_account or authorized_user file, or a
+// client_secret.json file) and check it for problems, returning a
+// ValidationReport
+func Validate(file string) *ValidationReport {
+	r := &ValidationReport{
+		File:     file,
+		Problems: []*Problem{},
+	}
+
+	f, err := ioutil.ReadFile(file)
+	if err != nil {
+		r.addError("", `Unable to read the file: `+err.Error())
+		return r.done()
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(f, &fields); err != nil {
+		r.addError("", `The file is not a valid JSON object: `+err.Error())
+		return r.done()
+	}
+
+	if raw, ok := fields["type"]; ok {
+		if err := json.Unmarshal(raw, &r.Type); err != nil {
+			r.addError("type", `The type field must be a string`)
+			return r.done()
+		}
+	} else if _, ok := fields["installed"]; ok {
+		r.Type = CredentialTypeClientSecret
+	} else if _, ok := fields["web"]; ok {
+		r.Type = CredentialTypeClientSecret
+	}
+
+	switch r.Type {
+	case CredentialTypeServiceAccount:
+		svAcc := &ServiceAccount{}
+		if r.unmarshal(f, svAcc) {
+			r.validateServiceAccount(svAcc)
+		}
+	case CredentialTypeExternalAccount:
+		e := &ExternalAccount{}
+		if r.unmarshal(f, e) {
+			r.validateExternalAccount(e)
+		}
+	case CredentialTypeAuthorizedUser:
+		user := &AuthorizedUser{}
+		if r.unmarshal(f, user) {
+			r.validateAuthorizedUser(user)
+		}
+	case CredentialTypeClientSecret:
+		secret := &ClientSecretFile{}
+		if r.unmarshal(f, secret) {
+			r.validateClientSecret(secret)
+		}
+	case "":
+		r.addError("type", `No type field found - expected a service_account, external_account or authorized_user file, or an "installed" or "web" client_secret.json file`)
+	default:
+		r.addError("type", `Unsupported credential type: `+r.Type+` - expected service_account, external_account or authorized_user`)
+	}
+
+	return r.done()
+}
+
+// Err method returns the ValidationReport's problems with an error
+// severity as a single error, or nil if the file is valid
+func (r *ValidationReport) Err() error {
+	var msgs []string
+	for _, p := range r.Problems {
+		if p.Severity == SeverityError {
+			msgs = append(msgs, p.String())
+		}
+	}
+
+	if len(msgs) == 0 {
+		return nil
+	}
+	return errors.New(`Invalid credentials file ` + r.File + `:
+
+	` + strings.Join(msgs, "\n\t"))
+}
+
+// Print method will output the ValidationReport's result and
+// problems
+func (r *ValidationReport) Print() {
+	var b strings.Builder
+
+	b.WriteString("====\n")
+	fmt.Fprintf(&b, "File: %s\n", r.File)
+	if r.Type != "" {
+		fmt.Fprintf(&b, "Type: %s\n", r.Type)
+	}
+	fmt.Fprintf(&b, "Valid: %s\n", strconv.FormatBool(r.Valid))
+	if len(r.Problems) > 0 {
+		b.WriteString("Problems:\n")
+		for _, p := range r.Problems {
+			fmt.Fprintf(&b, "\t[%s] %s\n", p.Severity, p.String())
+		}
+	}
+	b.WriteString("====")
+
+	fmt.Println(b.String())
+}
+
+// String method returns the Problem's message, prefixed by its field
+// if set
+func (p *Problem) String() string {
+	if p.Field == "" {
+		return p.Message
+	}
+	return p.Field + `: ` + p.Message
+}
+
+func (r *ValidationReport) addError(field, message string) {
+	r.Problems = append(r.Problems, &Problem{Field: field, Severity: SeverityError, Message: message})
+}
+
+func (r *ValidationReport) addWarning(field, message string) {
+	r.Problems = append(r.Problems, &Problem{Field: field, Severity: SeverityWarning, Message: message})
+}
+
+func (r *ValidationReport) done() *ValidationReport {
+	r.Valid = true
+	for _, p := range r.Problems {
+		if p.Severity == SeverityError {
+			r.Valid = false
+		}
+	}
+	return r
+}
+
+func (r *ValidationReport) unmarshal(data []byte, v interface{}) bool {
+	if err := json.Unmarshal(data, v); err != nil {
+		r.addError("", `Unable to parse the `+r.Type+` file: `+err.Error())
+		return false
+	}
+	return true
+}
+
+// required method adds an error if the input field's value is empty,
+// returning whether it is set
+func (r *ValidationReport) required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		r.addError(field, `Missing mandatory field`)
+		return false
+	}
+	return true
+}
+
+func (r *ValidationReport) checkURL(field, value string) {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		r.addError(field, `Invalid URL: `+value)
+		return
+	}
+	if u.Scheme != "https" {
+		r.addWarning(field, `URL is not using HTTPS: `+value)
+	}
+}
+
+func (r *ValidationReport) validateServiceAccount(s *ServiceAccount) {
+	if s.PrivateKeyID == "" {
+		r.addWarning("private_key_id", `Missing private key ID - required for self-signed JWTs (keys converted from .p12 files don't have one)`)
+	}
+
+	if r.required("private_key", s.PrivateKey) {
+		key, err := newKey([]byte(s.PrivateKey))
+		if err != nil {
+			r.addError("private_key", `Unable to parse the private key (`+err.Error()+`) - make sure it is copied as-is from the keyfile, including the BEGIN and END lines`)
+		} else if bits := key.N.BitLen(); bits < minRSAKeyBits {
+			r.addError("private_key", `RSA key is too small (`+strconv.Itoa(bits)+` bits) - at least `+strconv.Itoa(minRSAKeyBits)+` bits are required`)
+		}
+	}
+
+	if r.required("client_email", s.ClientEmail) {
+		if _, err := mail.ParseAddress(s.ClientEmail); err != nil {
+			r.addError("client_email", `Invalid email address: `+s.ClientEmail)
+		} else if !strings.HasSuffix(s.ClientEmail, ".gserviceaccount.com") {
+			r.addWarning("client_email", `Not a service account email (expected a *.gserviceaccount.com address): `+s.ClientEmail)
+		}
+	}
+
+	if r.required("token_uri", s.TokenURI) {
+		r.checkURL("token_uri", s.TokenURI)
+	}
+
+	if s.ProjectID == "" {
+		r.addWarning("project_id", `Missing project ID`)
+	}
+	if s.ClientID == "" {
+		r.addWarning("client_id", `Missing client ID - required to set up Workspace Domain-wide Delegation`)
+	}
+}
+
+func (r *ValidationReport) validateExternalAccount(e *ExternalAccount) {
+	if r.required("audience", e.Audience) && !strings.HasPrefix(e.Audience, "//iam.googleapis.com/") {
+		r.addWarning("audience", `Not a Workload Identity pool provider (expected //iam.googleapis.com/...): `+e.Audience)
+	}
+	r.required("subject_token_type", e.SubjectTokenType)
+
+	if e.TokenURL != "" {
+		r.checkURL("token_url", e.TokenURL)
+	}
+
+	if e.ServiceAccountImpersonationURL != "" {
+		r.checkURL("service_account_impersonation_url", e.ServiceAccountImpersonationURL)
+		if _, err := e.impersonation(""); err != nil {
+			r.addError("service_account_impersonation_url", `Expected a .../`+serviceAccountPrefix+`{email}:generateAccessToken URL`)
+		}
+	}
+
+	c := e.CredentialSource
+	if c == nil {
+		r.addError("credential_source", `Missing mandatory field`)
+		return
+	}
+
+	switch {
+	case c.File != "" && c.URL != "":
+		r.addError("credential_source", `Only one of file or url can be set`)
+	case c.File != "":
+		if _, err := ioutil.ReadFile(c.File); err != nil {
+			r.addWarning("credential_source.file", `Unable to read the subject token file: `+err.Error())
+		}
+	case c.URL != "":
+		r.checkURL("credential_source.url", c.URL)
+	default:
+		r.addError("credential_source", `Unsupported credential source - only file and url sources are supported`)
+	}
+
+	if c.Format != nil {
+		switch c.Format.Type {
+		case "", credentialFormatText:
+		case credentialFormatJSON:
+			r.required("credential_source.format.subject_token_field_name", c.Format.SubjectTokenFieldName)
+		default:
+			r.addError("credential_source.format.type", `Invalid format: `+c.Format.Type+` - must be either text or json`)
+		}
+	}
+}
+
+func (r *ValidationReport) validateAuthorizedUser(u *AuthorizedUser) {
+	r.required("client_id", u.ClientID)
+	r.required("client_secret", u.ClientSecret)
+	r.required("refresh_token", u.RefreshToken)
+}
+
+func (r *ValidationReport) validateClientSecret(f *ClientSecretFile) {
+	field := "installed"
+	if f.IsWeb() {
+		field = "web"
+	}
+
+	secret := f.Get()
+	if secret == nil {
+		r.addError(field, `Missing OAuth client details`)
+		return
+	}
+
+	r.required(field+".client_id", secret.ClientID)
+	r.required(field+".client_secret", secret.ClientSecret)
+
+	if r.required(field+".auth_uri", secret.AuthURI) {
+		r.checkURL(field+".auth_uri", secret.AuthURI)
+	}
+	if r.required(field+".token_uri", secret.TokenURI) {
+		r.checkURL(field+".token_uri", secret.TokenURI)
+	}
+
+	if f.IsWeb() && len(secret.RedirectURIs) == 0 {
+		r.addError("web.redirect_uris", `Web application clients require at least one registered redirect URI`)
+	}
+}